@@ -0,0 +1,57 @@
+// Turns a recorded classic game into an animated GIF, without ebiten so it runs headless
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"mkoca/2048/src/config"
+	"mkoca/2048/src/replay"
+)
+
+func main() {
+	recording := flag.String("recording", "", "recording to export instead of the last classic game")
+	delay := flag.Duration("delay", replay.GIF_DELAY, "how long every board is shown")
+	cellSize := flag.Int("cell-size", replay.GIF_CELL_SIZE, "cell size in pixels")
+	highlight := flag.Bool("highlight", true, "mark the direction of every move")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: export-gif [flags] output.gif")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	opts := replay.NewGIFOptions(*delay, *cellSize, *highlight)
+	if err := export(flag.Arg(0), *recording, opts); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// Renders a recording, or the last classic game without one, into an animated GIF
+func export(path string, recording string, opts replay.GIFOptions) error {
+	if recording == "" {
+		last, err := config.Path(replay.LAST_GAME_FILE)
+		if err != nil {
+			return err
+		}
+		recording = last
+	}
+
+	r, err := replay.LoadRecording(recording)
+	if err != nil {
+		return err
+	}
+
+	if err := replay.SaveGIF(path, r, opts); err != nil {
+		return err
+	}
+
+	log.Printf("wrote %d boards to %s", r.Len(), path)
+	return nil
+}
@@ -1,32 +1,161 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"log"
+	"os"
 	"runtime"
+	"strings"
 
 	"mkoca/2048/src/game"
+	"mkoca/2048/src/sound"
 
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
 func main() {
-	x, y := ebiten.Monitor().Size()
+	mode := flag.String("mode", "menu", "game mode to start: menu, classic, puzzle, editor, daily, versus, adversarial, evil or keys")
+	position := flag.String("position", "", "starting position of the classic mode, a position file or a board in notation like \"11../..../..../.... m 0 0\"")
+	printBoard := flag.Bool("print-board", false, "print the starting board of -position in notation and exit")
+	target := flag.Int("target", game.WIN_TILE, "tile that wins a versus match")
+	sameSeed := flag.Bool("same-seed", false, "give both versus players the same spawns")
+	defaults := game.DefaultSettings()
+	theme := flag.String("theme", "", "bundled theme name (classic, dark, high-contrast, deuteranopia, protanopia, tritanopia) or theme file")
+	fastForward := flag.Bool("fast-forward", defaults.FastForward, "finish animations as soon as the next move is pressed")
+	size := flag.Int("size", defaults.BoardSize, "cells per side of new boards")
+	fourChance := flag.Float64("four-chance", defaults.FourChance, "chance of a new tile being a 4, between 0 and 1")
+	animationSpeed := flag.Float64("animation-speed", defaults.AnimationSpeed, "how many times as fast animations play")
+	reducedMotion := flag.Bool("reduced-motion", defaults.ReducedMotion, "skip animations")
+	volume := flag.Float64("volume", defaults.SoundVolume, "sound volume between 0 and 1")
+	keyPreset := flag.String("key-preset", defaults.KeyPreset, "key preset: arrows, wasd or hjkl")
+	undoLimit := flag.Int("undo", defaults.UndoLimit, "number of moves that can be undone, 0 turns undo off")
+	flag.Parse()
 
-	// TODO
-	// WASM should also scale properly to users screen
-	if runtime.GOOS == "js" && runtime.GOARCH == "wasm" {
-		ebiten.SetWindowTitle("2048!")
-		ebiten.SetWindowSize(800, 600)
-	} else {
-		ebiten.SetWindowSize(x/2, y/2)
-		ebiten.SetWindowTitle("2048!")
-		ebiten.SetTPS(game.TARGET_TPS)
+	// The settings file is read before the first game is created, flags only override it for this run
+	settings, err := game.LoadSettings()
+	if err != nil {
+		log.Println("settings are not available, using the defaults:", err)
+	}
+	if err := game.ApplySettings(settings); err != nil {
+		log.Println("invalid settings, using the defaults:", err)
+		settings = game.DefaultSettings()
+		game.ApplySettings(settings)
 	}
 
-	if err := ebiten.RunGame(game.InitGame()); err != nil {
+	overridden := false
+	flag.Visit(func(f *flag.Flag) {
+		overridden = true
+		switch f.Name {
+		case "theme":
+			settings.Theme = *theme
+		case "fast-forward":
+			settings.FastForward = *fastForward
+		case "size":
+			settings.BoardSize = *size
+		case "four-chance":
+			settings.FourChance = *fourChance
+		case "animation-speed":
+			settings.AnimationSpeed = *animationSpeed
+		case "reduced-motion":
+			settings.ReducedMotion = *reducedMotion
+		case "volume":
+			settings.SoundVolume = *volume
+		case "key-preset":
+			settings.KeyPreset, settings.Keys = *keyPreset, nil
+		case "undo":
+			settings.UndoLimit = *undoLimit
+		}
+	})
+	if overridden {
+		if err := game.ApplySettings(settings); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if *printBoard {
+		g, err := loadPosition(*position)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(game.NotationOf(g))
+		return
+	}
+
+	sound.Init()
+
+	ebiten.SetWindowTitle("2048!")
+	ebiten.SetTPS(game.TARGET_TPS)
+	// The app saves the game in progress before it quits
+	ebiten.SetWindowClosingHandled(true)
+
+	// The browser build fills the page instead, see wasm/main.html
+	if runtime.GOOS != "js" || runtime.GOARCH != "wasm" {
+		x, y := ebiten.Monitor().Size()
+		ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
+
+		if *mode == "versus" {
+			// Two boards need the extra width
+			ebiten.SetWindowSize(x*3/4, y/2)
+		} else {
+			ebiten.SetWindowSize(x/2, y/2)
+		}
+	}
+
+	// Every mode sits on top of the main menu, leaving it goes back there
+	app := game.NewApp()
+	app.SetScreen(game.NewMainMenu(app))
+
+	switch *mode {
+	case "menu":
+	case "puzzle":
+		app.Push(game.NewLevelSelect(app))
+	case "editor":
+		app.Push(game.NewEditor(app))
+	case "daily":
+		app.Push(game.NewDailyGame(app))
+	case "versus":
+		app.Push(game.NewVersusGame(app, *target, *sameSeed))
+	case "adversarial":
+		app.Push(game.NewAdversarialGame(app, false))
+	case "evil":
+		app.Push(game.NewAdversarialGame(app, true))
+	case "keys":
+		app.Push(game.NewKeyBindingScreen(app))
+	default:
+		if *position != "" {
+			g, err := loadPosition(*position)
+			if err != nil {
+				log.Fatal(err)
+			}
+			app.Push(game.NewClassicGame(app, g))
+		} else {
+			app.Push(game.NewClassicGame(app, game.InitGame()))
+		}
+	}
+
+	if err := ebiten.RunGame(app); err != nil {
 		if err.Error() == "SIGKILL" {
 			return
 		}
 		log.Fatal(err)
 	}
 }
+
+// Position files end in .json or exist on disk, anything else is read as notation
+func loadPosition(position string) (*game.Game, error) {
+	if _, err := os.Stat(position); err == nil || strings.HasSuffix(position, ".json") {
+		pos, err := game.LoadPosition(position)
+		if err != nil {
+			return nil, err
+		}
+		return pos.NewGame(), nil
+	}
+
+	n, err := game.ParseNotation(position)
+	if err != nil {
+		return nil, fmt.Errorf("-position is neither a position file nor a board in notation: %w", err)
+	}
+
+	return n.NewGame(), nil
+}
@@ -0,0 +1,19 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Directory under os.UserConfigDir where the game keeps its files
+var DIR_NAME = "go-2048"
+
+// Path of a file in the config directory, shared by the game and its tools
+func Path(name string) (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, DIR_NAME, name), nil
+}
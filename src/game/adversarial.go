@@ -0,0 +1,176 @@
+package game
+
+import (
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+type AdversaryTurn int32
+
+const (
+	TURN_SLIDE AdversaryTurn = iota
+	TURN_SPAWN
+)
+
+// One player slides with the move keys of the current bindings while the other decides every spawn
+// With a nil manual spawner the EvilSpawner plays the spawning side
+type AdversarialGame struct {
+	app    *App
+	game   *Game
+	manual *ManualSpawner
+	turn   AdversaryTurn
+	// Why the last chosen spawn cell was rejected, cleared once a spawn goes through
+	rejected string
+}
+
+func NewAdversarialGame(app *App, evil bool) *AdversarialGame {
+	ag := AdversarialGame{app: app}
+
+	var spawner Spawner = &EvilSpawner{}
+	if !evil {
+		ag.manual = &ManualSpawner{}
+		spawner = ag.manual
+	}
+
+	ag.game = NewGame(NewBoard(CELL_COUNT), spawner)
+	ag.game.layout.spec = LayoutSpec{boxes: 2, footerLines: 1}
+	ag.reset()
+
+	return &ag
+}
+
+func (ag *AdversarialGame) reset() {
+	// The manual spawner has no pick yet and places nothing, the spawning player places the first tile
+	ResetGame(ag.game)
+	ag.rejected = ""
+
+	ag.turn = TURN_SLIDE
+	if ag.manual != nil {
+		ag.turn = TURN_SPAWN
+	}
+}
+
+func (ag *AdversarialGame) Update() error {
+	if Bindings().JustPressed(ACTION_QUIT) {
+		ag.app.Pop()
+		return nil
+	}
+
+	g := ag.game
+	AdvanceAnimations(g)
+	AssignGamepads([]*Game{g})
+
+	if g.status != RUNNING {
+		if inpututil.IsKeyJustPressed(ebiten.KeyR) {
+			ag.reset()
+		}
+		return nil
+	}
+
+	if ag.turn == TURN_SLIDE {
+		// Without a manual spawner this is the classic loop with an evil spawner
+		moves := g.moves
+		updateRunning(g)
+		if ag.manual != nil && g.moves > moves {
+			ag.turn = TURN_SPAWN
+		}
+		return nil
+	}
+
+	left := inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft)
+	right := inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight)
+	if !left && !right {
+		return nil
+	}
+
+	cx, cy := ebiten.CursorPosition()
+	row, col, ok := CellAt(g.board, cx, cy)
+	if !ok {
+		return nil
+	}
+
+	s := Spawn{pos_x: row, pos_y: col, val: 2}
+	if right {
+		s.val = 4
+	}
+
+	ag.manual.Choose(s)
+	if err := ag.spawn(); err != nil {
+		ag.rejected = "That cell is taken, pick an empty one"
+		return nil
+	}
+
+	ag.rejected = ""
+	ag.turn = TURN_SLIDE
+	return nil
+}
+
+// The very first tile is placed directly, every later one with the usual animation
+// Fails when the spawner rejected the chosen cell, the turn then stays with the spawning player
+func (ag *AdversarialGame) spawn() error {
+	if MaxTile(ag.game.board.cells) == 0 {
+		return PlaceSpawn(ag.game)
+	}
+
+	return SpawnCell(ag.game)
+}
+
+func (ag *AdversarialGame) Draw(screen *ebiten.Image) {
+	g := ag.game
+
+	drawBackground(g, screen)
+	drawBoard(g, screen)
+
+	x, y := InfoBoxPosition(g, 0)
+	drawScoreboard(g, screen, x, y)
+
+	turn := "SLIDE"
+	hint := "Player 1: move with " + Bindings().MoveLabel()
+	if ag.manual == nil {
+		turn = "EVIL AI"
+		hint = "Survive the evil spawner"
+	} else if ag.turn == TURN_SPAWN {
+		turn = "SPAWN"
+		hint = "Player 2: left click spawns a 2, right click a 4"
+		if ag.rejected != "" {
+			hint = ag.rejected
+		}
+
+		// Highlight the empty cell under the cursor
+		cx, cy := ebiten.CursorPosition()
+		if row, col, ok := CellAt(g.board, cx, cy); ok {
+			c := g.board.cells[row][col]
+			if !c.isRendered && !c.isBlocked && c.animation == nil {
+				fillRect(screen, c.x, c.y, CELL_SIZE, CELL_SIZE, CurrentTheme().Radius(CELL_SIZE), CurrentTheme().overlay)
+			}
+		}
+	}
+	x, y = InfoBoxPosition(g, 1)
+	drawInfoBox(g, screen, x, y, "TURN", turn)
+
+	txtOp := &text.DrawOptions{}
+	txtOp.ColorScale.ScaleWithColor(CurrentTheme().mutedText)
+	caption := Fonts().Face(FONT_CAPTION)
+	cx, top := FooterPosition(g)
+	DrawCenteredText(screen, caption, hint, cx, top+int(caption.Size*1.5), txtOp)
+
+	switch g.status {
+	case FINISHED:
+		drawOverlay(g, screen)
+		drawAfterGameText(g, screen, "The slider wins!", "R to play again")
+	case GAME_OVER:
+		drawOverlay(g, screen)
+		drawAfterGameText(g, screen, "The spawner wins!", "R to play again")
+	}
+}
+
+func (ag *AdversarialGame) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
+	return ScaledSize(outsideWidth, outsideHeight)
+}
+
+func (ag *AdversarialGame) Resize(width int, height int) {
+	ApplyLayout(ag.game, image.Rect(0, 0, width, height))
+}
@@ -0,0 +1,54 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAdversarialReset(t *testing.T) {
+	defer SetCellSize(CELL_SIZE)
+
+	testCases := []struct {
+		name          string
+		evil          bool
+		expectedTurn  AdversaryTurn
+		expectedTiles int
+	}{
+		{name: "manual", evil: false, expectedTurn: TURN_SPAWN, expectedTiles: 0},
+		{name: "evil", evil: true, expectedTurn: TURN_SLIDE, expectedTiles: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ag := NewAdversarialGame(NewApp(), tc.evil)
+			g := ag.game
+
+			// Leftovers of the last round
+			g.score, g.moves, g.status = 64, 12, GAME_OVER
+			g.effects = append(g.effects, ScoreGainAnimation(8, time.Second))
+			g.history = append(g.history, TakeSnapshot(g))
+			g.input.Push(LEFT)
+			ag.rejected = "That cell is taken, pick an empty one"
+
+			ag.reset()
+
+			tiles := 0
+			for _, row := range cellValues(g.board.cells) {
+				for _, v := range row {
+					if v > 0 {
+						tiles++
+					}
+				}
+			}
+
+			if ag.turn != tc.expectedTurn || tiles != tc.expectedTiles {
+				t.Errorf("expected turn %d with %d tiles, found turn %d with %d", tc.expectedTurn, tc.expectedTiles, ag.turn, tiles)
+			}
+
+			if g.score != 0 || g.moves != 0 || g.status != RUNNING || len(g.effects) != 0 || len(g.history) != 0 || g.input.Len() != 0 || ag.rejected != "" {
+				t.Errorf("expected a clean round, found score %d, %d moves, status %d, %d effects, %d undo steps, %d queued moves and %q",
+					g.score, g.moves, g.status, len(g.effects), len(g.history), g.input.Len(), ag.rejected)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package game
+
+import (
+	"errors"
+	"math"
+)
+
+var DIRECTIONS = []Direction{UP, RIGHT, DOWN, LEFT}
+
+// Picks the spawn that leaves the sliding player with the worst best reply
+// Both 2 and 4 are considered on every empty cell
+type EvilSpawner struct{}
+
+// impl EvilSpawner
+
+func (spawner *EvilSpawner) NextSpawn(cells [][]Cell) (Spawn, error) {
+	best := Spawn{}
+	bestScore := math.MaxInt
+	found := false
+
+	for i, row := range cells {
+		for j, c := range row {
+			if c.isRendered || c.isBlocked {
+				continue
+			}
+
+			for _, val := range []int{2, 4} {
+				trial := CopyCells(cells)
+				trial[i][j].isRendered = true
+				trial[i][j].val = val
+
+				score := bestReplyScore(trial)
+				if score < bestScore {
+					best = Spawn{pos_x: i, pos_y: j, val: val}
+					bestScore = score
+					found = true
+				}
+			}
+		}
+	}
+
+	if !found {
+		return Spawn{}, errors.New("no empty cells found")
+	}
+
+	return best, nil
+}
+
+// end
+
+// Deep copy of the board values, animations are not copied
+func CopyCells(cells [][]Cell) [][]Cell {
+	copied := make([][]Cell, len(cells))
+	for i, row := range cells {
+		copied[i] = make([]Cell, len(row))
+		copy(copied[i], row)
+
+		for j := range copied[i] {
+			copied[i][j].animation = nil
+		}
+	}
+
+	return copied
+}
+
+// Plays a move on a copy of the board
+// Returns the resulting cells, whether anything changed and the merge score
+func SimulateMove(cells [][]Cell, d Direction) ([][]Cell, bool, int) {
+	g := Game{board: Board{cells: CopyCells(cells)}}
+	totalNumOfMovements, totalMergeScore := Move(&g, d)
+
+	return g.board.cells, totalNumOfMovements > 0 || totalMergeScore > 0, totalMergeScore
+}
+
+// Heuristic value of a board for the sliding player, higher is better
+// Every empty cell counts most, neighbouring equal tiles are the next best thing
+func EvaluateBoard(cells [][]Cell) int {
+	score := 0
+
+	for i, row := range cells {
+		for j, c := range row {
+			if c.isBlocked {
+				continue
+			}
+
+			if !c.isRendered {
+				score += 4
+				continue
+			}
+
+			if j+1 < len(row) && row[j+1].isRendered && row[j+1].val == c.val {
+				score++
+			}
+
+			if i+1 < len(cells) && cells[i+1][j].isRendered && cells[i+1][j].val == c.val {
+				score++
+			}
+		}
+	}
+
+	return score
+}
+
+// Best direction for the sliding player by one move look ahead
+// Returns false when no move changes the board
+func BestMove(cells [][]Cell) (Direction, bool) {
+	d, _, ok := bestMove(cells)
+	return d, ok
+}
+
+// Value of the sliding player's best reply, math.MinInt when they are stuck
+func bestReplyScore(cells [][]Cell) int {
+	_, score, ok := bestMove(cells)
+	if !ok {
+		return math.MinInt
+	}
+
+	return score
+}
+
+func bestMove(cells [][]Cell) (Direction, int, bool) {
+	best, bestScore, found := UP, math.MinInt, false
+
+	for _, d := range DIRECTIONS {
+		after, moved, mergeScore := SimulateMove(cells, d)
+		if !moved {
+			continue
+		}
+
+		score := EvaluateBoard(after)*16 + mergeScore
+		if score > bestScore {
+			best, bestScore, found = d, score, true
+		}
+	}
+
+	return best, bestScore, found
+}
@@ -0,0 +1,62 @@
+package game
+
+import "testing"
+
+func TestEvilSpawnerForcesGameOver(t *testing.T) {
+	cells := [][]Cell{{m(2), m(4)}, {m(8), m(0)}}
+
+	s, err := (&EvilSpawner{}).NextSpawn(cells)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A 4 would allow the vertical merge, a 2 leaves no move at all
+	expected := Spawn{pos_x: 1, pos_y: 1, val: 2}
+	if s != expected {
+		t.Errorf("expected %+v, found %+v", expected, s)
+	}
+}
+
+func TestEvilSpawnerFullBoard(t *testing.T) {
+	cells := [][]Cell{{m(2), m(4)}, {m(8), m(16)}}
+
+	_, err := (&EvilSpawner{}).NextSpawn(cells)
+	if err == nil {
+		t.Errorf("spawning on a full board should fail")
+	}
+}
+
+func TestBestMove(t *testing.T) {
+	d, ok := BestMove([][]Cell{{m(2), m(2)}, {m(0), m(0)}})
+	if !ok || d != RIGHT {
+		t.Errorf("expected a horizontal merge to the right, found %d, %t", d, ok)
+	}
+
+	_, ok = BestMove([][]Cell{{m(2), m(4)}, {m(8), m(16)}})
+	if ok {
+		t.Errorf("no move should be found on a stuck board")
+	}
+}
+
+func TestManualSpawner(t *testing.T) {
+	cells := [][]Cell{{m(2), m(0)}, {m(0), m(0)}}
+	spawner := ManualSpawner{}
+
+	if _, err := spawner.NextSpawn(cells); err == nil {
+		t.Errorf("spawning without a choice should fail")
+	}
+
+	spawner.Choose(Spawn{pos_x: 0, pos_y: 0, val: 4})
+	if _, err := spawner.NextSpawn(cells); err == nil {
+		t.Errorf("spawning on an occupied cell should fail")
+	}
+
+	spawner.Choose(Spawn{pos_x: 1, pos_y: 0, val: 4})
+	if s, err := spawner.NextSpawn(cells); err != nil || s.val != 4 {
+		t.Errorf("expected the chosen spawn, found %+v, %v", s, err)
+	}
+
+	if _, err := spawner.NextSpawn(cells); err == nil {
+		t.Errorf("a choice should only be used once")
+	}
+}
@@ -2,6 +2,12 @@ package game
 
 import (
 	"errors"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
 )
 
 type AnimationType int32
@@ -10,77 +16,176 @@ type AnimationStatus int32
 const (
 	ANIM_CREATE_CELL AnimationType = iota
 	ANIM_MOVE_CELL
+	ANIM_POP_CELL
+	ANIM_SCORE
+	ANIM_DELAY
+	ANIM_SEQUENCE
+	ANIM_PARALLEL
 )
 
 const (
 	ANIM_CREATED AnimationStatus = iota
 	ANIM_RUNNING
 	ANIM_FINISHED
+	ANIM_CANCELLED
 )
 
+// Animations are driven by elapsed time
+// Callbacks registered with OnComplete run once the animation finishes, a cancelled animation never runs them
 type Animation interface {
 	GetType() AnimationType
 	GetStatus() AnimationStatus
-	GetLength() int
-	Step() error
-	OnFinish(Animation, *Game)
+	GetLength() time.Duration
+	Step(dt time.Duration) error
+	Cancel()
+	OnComplete(func())
+	Draw(*ebiten.Image, *Game)
+}
+
+// Timing shared by every animation
+type animationClock struct {
+	status    AnimationStatus
+	duration  time.Duration
+	elapsed   time.Duration
+	easing    Easing
+	callbacks []func()
 }
 
+// Grows a freshly spawned tile from half size to full size
 type CreateAnimation struct {
-	animationType   AnimationType
-	animationStatus AnimationStatus
-	duration        int
-	position        Cell
-	startingSize    int
-	targetSize      int
-	currentSize     int
-	step            int
+	animationClock
+	position Cell
+	value    int
 }
 
+// Slides a tile from start_pos to end_pos, both in screen coordinates
+// For a merge a second tile slides in from merge_pos, both show the value from before the merge
 type MoveAnimation struct {
-	animationType   AnimationType
-	animationStatus AnimationStatus
-	duration        int
-	start_pos       Vec2
-	end_pos         Vec2
-	step            int
+	animationClock
+	start_pos Vec2
+	end_pos   Vec2
+	merge_pos Vec2
+	isMerge   bool
+	position  Cell
+	value     int
 }
 
-// impl CreateAnimation
+// Briefly scales a freshly merged tile up and back
+type PopAnimation struct {
+	animationClock
+	position Cell
+}
 
-func (animation *CreateAnimation) GetType() AnimationType {
-	return ANIM_CREATE_CELL
+// A "+N" that rises and fades above the score box
+type ScoreAnimation struct {
+	animationClock
+	value int
 }
 
-func (animation *CreateAnimation) GetStatus() AnimationStatus {
-	return animation.animationStatus
+// Waits without drawing anything, used to hold back the next step of a sequence
+type DelayAnimation struct {
+	animationClock
 }
 
-func (animation *CreateAnimation) GetLength() int {
-	return animation.duration
+// Plays its children one after the other, time left over when a child finishes goes to the next one
+// Cancelled children are skipped
+type SequenceAnimation struct {
+	animationClock
+	children []Animation
+	current  int
+	// Elapsed time of the sequence when the current child started
+	started time.Duration
 }
 
-func (animation *CreateAnimation) Step() error {
-	if animation.animationStatus == ANIM_FINISHED {
+// Plays its children at the same time and finishes with the longest one, cancelled children are skipped
+type ParallelAnimation struct {
+	animationClock
+	children []Animation
+}
+
+// impl animationClock
+
+func (clock *animationClock) GetStatus() AnimationStatus {
+	return clock.status
+}
+
+func (clock *animationClock) GetLength() time.Duration {
+	return clock.duration
+}
+
+func (clock *animationClock) Step(dt time.Duration) error {
+	if clock.status == ANIM_FINISHED || clock.status == ANIM_CANCELLED {
 		return errors.New("step called on already finished animation")
 	}
 
-	animation.animationStatus = ANIM_RUNNING
-	animation.currentSize += animation.step
-	if animation.currentSize >= animation.targetSize {
-		animation.animationStatus = ANIM_FINISHED
+	clock.status = ANIM_RUNNING
+	clock.elapsed += dt
+	if clock.elapsed >= clock.duration {
+		clock.elapsed = clock.duration
+		clock.finish()
 	}
 
 	return nil
 }
 
-func (animation *CreateAnimation) OnFinish(anim Animation, g *Game) {
-	a := anim.(*CreateAnimation)
+func (clock *animationClock) Cancel() {
+	if clock.status != ANIM_FINISHED {
+		clock.status = ANIM_CANCELLED
+	}
+}
+
+func (clock *animationClock) OnComplete(fn func()) {
+	clock.callbacks = append(clock.callbacks, fn)
+}
+
+// Eased progress between 0 and 1
+func (clock *animationClock) Progress() float64 {
+	t := 1.0
+	if clock.duration > 0 {
+		t = min(float64(clock.elapsed)/float64(clock.duration), 1)
+	}
+
+	if clock.easing == nil {
+		return t
+	}
+
+	return clock.easing(t)
+}
+
+func (clock *animationClock) finish() {
+	clock.status = ANIM_FINISHED
+	for _, fn := range clock.callbacks {
+		fn()
+	}
+}
+
+// end
+
+// impl CreateAnimation
+
+func (animation *CreateAnimation) GetType() AnimationType {
+	return ANIM_CREATE_CELL
+}
+
+func (animation *CreateAnimation) Size() int {
+	start := float64(CELL_SIZE) / 2
+	return int(start + (float64(CELL_SIZE)-start)*animation.Progress())
+}
+
+func (animation *CreateAnimation) Draw(screen *ebiten.Image, g *Game) {
+	c := animation.position
+	size := animation.Size()
+	if size <= 0 {
+		return
+	}
+
+	cx := c.x + CELL_SIZE/2 // center x
+	cy := c.y + CELL_SIZE/2 // center y
+
+	cx = cx - size/2 // center x offsetted by current size
+	cy = cy - size/2 // center y offsetted by current size
 
-	cellPos := a.position
-	c := &g.board.cells[cellPos.pos_x][cellPos.pos_y]
-	c.isRendered = true
-	c.val = 2
+	fillRect(screen, cx, cy, size, size, CurrentTheme().Radius(size), GetColor(animation.value))
 }
 
 // end
@@ -91,36 +196,299 @@ func (animation *MoveAnimation) GetType() AnimationType {
 	return ANIM_MOVE_CELL
 }
 
-func (animation *MoveAnimation) GetStatus() AnimationStatus {
-	return animation.animationStatus
+// Current position of the sliding tile that started at from
+func (animation *MoveAnimation) Position(from Vec2) Vec2 {
+	t := animation.Progress()
+	return Vec2{
+		x: from.x + int(math.Round(float64(animation.end_pos.x-from.x)*t)),
+		y: from.y + int(math.Round(float64(animation.end_pos.y-from.y)*t)),
+	}
+}
+
+func (animation *MoveAnimation) Draw(screen *ebiten.Image, g *Game) {
+	if animation.isMerge {
+		p := animation.Position(animation.merge_pos)
+		drawTile(screen, g, p.x, p.y, CELL_SIZE, animation.value)
+	}
+
+	p := animation.Position(animation.start_pos)
+	drawTile(screen, g, p.x, p.y, CELL_SIZE, animation.value)
+}
+
+// end
+
+// impl PopAnimation
+
+func (animation *PopAnimation) GetType() AnimationType {
+	return ANIM_POP_CELL
+}
+
+func (animation *PopAnimation) Scale() float64 {
+	return 1 + POP_SCALE*math.Sin(math.Pi*animation.Progress())
+}
+
+func (animation *PopAnimation) Draw(screen *ebiten.Image, g *Game) {
+	c := animation.position
+	size := int(float64(CELL_SIZE) * animation.Scale())
+	offset := (size - CELL_SIZE) / 2
+
+	drawTile(screen, g, c.x-offset, c.y-offset, size, c.val)
+}
+
+// end
+
+// impl ScoreAnimation
+
+func (animation *ScoreAnimation) GetType() AnimationType {
+	return ANIM_SCORE
+}
+
+// Draws above the top center of the score box last drawn by drawScoreboard
+func (animation *ScoreAnimation) Draw(screen *ebiten.Image, g *Game) {
+	t := animation.Progress()
+	rise := int(float64(CELL_SIZE) / 2 * EaseOutQuad(t))
+
+	txtOp := &text.DrawOptions{}
+	txtOp.ColorScale.ScaleWithColor(CurrentTheme().text)
+	txtOp.ColorScale.ScaleAlpha(float32(1 - t))
+	DrawCenteredText(screen, Fonts().Face(FONT_BODY), "+"+strconv.Itoa(animation.value), g.scorePos.x, g.scorePos.y-rise, txtOp)
 }
 
-func (animation *MoveAnimation) GetLength() int {
-	return animation.duration
+// end
+
+// impl DelayAnimation
+
+func (animation *DelayAnimation) GetType() AnimationType {
+	return ANIM_DELAY
 }
 
-func (animation *MoveAnimation) Step() error {
-	// TODO
+func (animation *DelayAnimation) Draw(screen *ebiten.Image, g *Game) {
+}
+
+// end
+
+// impl SequenceAnimation
+
+func (animation *SequenceAnimation) GetType() AnimationType {
+	return ANIM_SEQUENCE
+}
+
+func (animation *SequenceAnimation) Step(dt time.Duration) error {
+	if animation.status == ANIM_FINISHED || animation.status == ANIM_CANCELLED {
+		return errors.New("step called on already finished animation")
+	}
+
+	animation.status = ANIM_RUNNING
+	animation.elapsed += dt
+	for animation.current < len(animation.children) {
+		child := animation.children[animation.current]
+		if child.GetStatus() == ANIM_CANCELLED {
+			animation.current++
+			animation.started = animation.elapsed - dt
+			continue
+		}
+
+		if err := child.Step(dt); err != nil {
+			return err
+		}
+
+		if child.GetStatus() != ANIM_FINISHED {
+			break
+		}
+
+		animation.current++
+		animation.started += child.GetLength()
+		dt = max(0, animation.elapsed-animation.started)
+	}
+
+	if animation.current >= len(animation.children) {
+		animation.finish()
+	}
+
+	return nil
+}
+
+func (animation *SequenceAnimation) Cancel() {
+	for _, child := range animation.children[min(animation.current, len(animation.children)):] {
+		child.Cancel()
+	}
+
+	animation.animationClock.Cancel()
+}
+
+func (animation *SequenceAnimation) Draw(screen *ebiten.Image, g *Game) {
+	if animation.current < len(animation.children) {
+		animation.children[animation.current].Draw(screen, g)
+	}
+}
+
+// end
+
+// impl ParallelAnimation
+
+func (animation *ParallelAnimation) GetType() AnimationType {
+	return ANIM_PARALLEL
+}
+
+func (animation *ParallelAnimation) Step(dt time.Duration) error {
+	if animation.status == ANIM_FINISHED || animation.status == ANIM_CANCELLED {
+		return errors.New("step called on already finished animation")
+	}
+
+	animation.status = ANIM_RUNNING
+	running := false
+	for _, child := range animation.children {
+		if child.GetStatus() == ANIM_FINISHED || child.GetStatus() == ANIM_CANCELLED {
+			continue
+		}
+
+		if err := child.Step(dt); err != nil {
+			return err
+		}
+
+		running = running || child.GetStatus() != ANIM_FINISHED
+	}
+
+	if !running {
+		animation.finish()
+	}
+
 	return nil
 }
 
-func (animation *MoveAnimation) OnFinish() {
+func (animation *ParallelAnimation) Cancel() {
+	for _, child := range animation.children {
+		child.Cancel()
+	}
 
+	animation.animationClock.Cancel()
+}
+
+func (animation *ParallelAnimation) Draw(screen *ebiten.Image, g *Game) {
+	for _, child := range animation.children {
+		if child.GetStatus() == ANIM_CREATED || child.GetStatus() == ANIM_RUNNING {
+			child.Draw(screen, g)
+		}
+	}
 }
 
 // end
 
-func CreateCellAnimation(cell Cell, durationInTicks int) *CreateAnimation {
-	animation := CreateAnimation{
-		animationType:   ANIM_CREATE_CELL,
-		animationStatus: ANIM_CREATED,
-		duration:        durationInTicks,
-		position:        cell,
-		startingSize:    CELL_SIZE / 2,
-		targetSize:      CELL_SIZE,
+func CreateCellAnimation(cell Cell, value int, duration time.Duration) *CreateAnimation {
+	return &CreateAnimation{
+		animationClock: animationClock{duration: duration, easing: EaseOutBack},
+		position:       cell,
+		value:          value,
+	}
+}
+
+// Slides the tile of cell from its origin, cell must be the state right after the move
+func MoveCellAnimation(cell Cell, duration time.Duration) *MoveAnimation {
+	animation := MoveAnimation{
+		animationClock: animationClock{duration: duration, easing: EaseOutCubic},
+		start_pos:      cell.origin,
+		end_pos:        Vec2{x: cell.x, y: cell.y},
+		merge_pos:      cell.mergeOrigin,
+		isMerge:        cell.merged,
+		position:       cell,
+		value:          cell.val,
+	}
+
+	if cell.merged {
+		animation.value = cell.val / 2
 	}
 
-	animation.currentSize = animation.startingSize
-	animation.step = max((animation.targetSize-animation.startingSize)/durationInTicks, 1)
 	return &animation
 }
+
+func PopCellAnimation(cell Cell, duration time.Duration) *PopAnimation {
+	return &PopAnimation{
+		animationClock: animationClock{duration: duration},
+		position:       cell,
+	}
+}
+
+func ScoreGainAnimation(value int, duration time.Duration) *ScoreAnimation {
+	return &ScoreAnimation{
+		animationClock: animationClock{duration: duration},
+		value:          value,
+	}
+}
+
+func Delay(duration time.Duration) *DelayAnimation {
+	return &DelayAnimation{animationClock: animationClock{duration: duration}}
+}
+
+func Sequence(children ...Animation) *SequenceAnimation {
+	animation := SequenceAnimation{children: children}
+	for _, child := range children {
+		animation.duration += child.GetLength()
+	}
+
+	return &animation
+}
+
+func Parallel(children ...Animation) *ParallelAnimation {
+	animation := ParallelAnimation{children: children}
+	for _, child := range children {
+		animation.duration = max(animation.duration, child.GetLength())
+	}
+
+	return &animation
+}
+
+// Advances every animation of the game by the real time passed since the last call, scaled by ANIMATION_SPEED
+// Long pauses between calls are clamped so animations do not jump to their end
+func AdvanceAnimations(g *Game) {
+	now := time.Now()
+	dt := time.Duration(0)
+	if !g.lastTick.IsZero() {
+		dt = min(now.Sub(g.lastTick), MAX_ANIMATION_STEP)
+	}
+	g.lastTick = now
+
+	if REDUCED_MOTION {
+		FinishAnimations(g)
+		return
+	}
+
+	StepAnimations(g, time.Duration(float64(dt)*ANIMATION_SPEED))
+}
+
+// Plays every running animation and score effect to its end at once, running all callbacks
+func FinishAnimations(g *Game) {
+	for HasRunningAnimation(g) || len(g.effects) > 0 {
+		StepAnimations(g, time.Hour)
+	}
+}
+
+// Steps every cell animation and score effect, dropping the ones that are done
+func StepAnimations(g *Game, dt time.Duration) {
+	for i, row := range g.board.cells {
+		for j := range row {
+			c := &g.board.cells[i][j]
+			if c.animation == nil {
+				continue
+			}
+
+			anim := c.animation
+			if anim.GetStatus() == ANIM_FINISHED || anim.GetStatus() == ANIM_CANCELLED || anim.Step(dt) != nil {
+				c.animation = nil
+				continue
+			}
+
+			// A completion callback may already have replaced the animation
+			if c.animation == anim && anim.GetStatus() == ANIM_FINISHED {
+				c.animation = nil
+			}
+		}
+	}
+
+	effects := g.effects[:0]
+	for _, effect := range g.effects {
+		if effect.Step(dt) == nil && effect.GetStatus() != ANIM_FINISHED {
+			effects = append(effects, effect)
+		}
+	}
+	g.effects = effects
+}
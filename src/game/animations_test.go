@@ -0,0 +1,236 @@
+package game
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestMoveTracksOrigins(t *testing.T) {
+	row := []Cell{m(2), m(0), m(2), m(4)}
+	for j := range row {
+		row[j].x = j * 100
+	}
+	g := &Game{board: Board{cells: [][]Cell{row}}}
+
+	Move(g, LEFT)
+
+	first, second := g.board.cells[0][0], g.board.cells[0][1]
+	if first.val != 4 || !first.merged || first.origin.x != 0 || first.mergeOrigin.x != 200 {
+		t.Errorf("expected the 2s from 0 and 200 to merge into the first cell, found %s origin %v merge %v", FormatCell(first), first.origin, first.mergeOrigin)
+	}
+
+	if second.val != 4 || second.merged || second.origin.x != 300 {
+		t.Errorf("expected the 4 from 300 to slide into the second cell, found %s origin %v", FormatCell(second), second.origin)
+	}
+}
+
+func TestMoveAnimation(t *testing.T) {
+	cell := Cell{x: 100, y: 0, val: 8, isRendered: true, origin: Vec2{x: 300}, mergeOrigin: Vec2{x: 200}, merged: true}
+	ma := MoveCellAnimation(cell, 4*time.Millisecond)
+	ma.easing = Linear
+
+	if ma.value != 4 {
+		t.Errorf("merging tiles should show the value before the merge, found %d", ma.value)
+	}
+
+	if p := ma.Position(ma.start_pos); p.x != 300 {
+		t.Errorf("expected to start at 300, found %d", p.x)
+	}
+
+	ma.Step(2 * time.Millisecond)
+
+	if p := ma.Position(ma.start_pos); p.x != 200 {
+		t.Errorf("expected to be half way at 200, found %d", p.x)
+	}
+
+	if p := ma.Position(ma.merge_pos); p.x != 150 {
+		t.Errorf("expected the merged tile half way at 150, found %d", p.x)
+	}
+
+	ma.Step(2 * time.Millisecond)
+
+	if ma.GetStatus() != ANIM_FINISHED || ma.Position(ma.start_pos).x != 100 {
+		t.Errorf("expected a finished animation at the destination")
+	}
+
+	if ma.Step(time.Millisecond) == nil {
+		t.Errorf("stepping a finished animation should fail")
+	}
+}
+
+func TestMergeStartsPop(t *testing.T) {
+	g := &Game{board: Board{cells: [][]Cell{{m(2), m(2)}, {m(0), m(0)}}}}
+	for i, row := range g.board.cells {
+		for j := range row {
+			g.board.cells[i][j].pos_x, g.board.cells[i][j].pos_y = i, j
+		}
+	}
+
+	Move(g, LEFT)
+	StartMoveAnimations(g)
+
+	c := &g.board.cells[0][0]
+	seq, ok := c.animation.(*SequenceAnimation)
+	if !ok {
+		t.Fatalf("expected a sequence on the merged cell")
+	}
+
+	if len(seq.children) != 2 || seq.children[0].GetType() != ANIM_MOVE_CELL || seq.children[1].GetType() != ANIM_POP_CELL {
+		t.Fatalf("expected the merged cell to slide and then pop")
+	}
+
+	StepAnimations(g, MOVE_CELL_ANIMATION_DURATION)
+	if c.animation == nil || seq.current != 1 {
+		t.Errorf("expected the pop to play after sliding")
+	}
+
+	StepAnimations(g, POP_ANIMATION_DURATION)
+	if c.animation != nil {
+		t.Errorf("expected the finished sequence to be removed")
+	}
+}
+
+func TestPopAnimationScale(t *testing.T) {
+	pa := PopCellAnimation(Cell{}, 4*time.Millisecond)
+	if pa.Scale() != 1 {
+		t.Errorf("pop should start at the normal size, found %f", pa.Scale())
+	}
+
+	pa.Step(2 * time.Millisecond)
+	if pa.Scale() <= 1 {
+		t.Errorf("pop should be larger half way, found %f", pa.Scale())
+	}
+
+	pa.Step(2 * time.Millisecond)
+	if pa.GetStatus() != ANIM_FINISHED || pa.Scale() > 1+1e-9 {
+		t.Errorf("pop should end finished at the normal size, found %f", pa.Scale())
+	}
+}
+
+func TestSpawnCompletesAfterDelay(t *testing.T) {
+	g := &Game{board: Board{cells: emptyCells(2)}, spawner: &ScriptedSpawner{spawns: []Spawn{{pos_x: 1, pos_y: 0, val: 4}}}}
+
+	if err := SpawnCellAfter(g, MOVE_CELL_ANIMATION_DURATION); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &g.board.cells[1][0]
+	StepAnimations(g, MOVE_CELL_ANIMATION_DURATION)
+	if c.isRendered || !HasRunningAnimation(g) {
+		t.Errorf("the tile should still be growing after the delay")
+	}
+
+	StepAnimations(g, CREATE_CELL_ANIMATION_DURATION)
+	if !c.isRendered || c.val != 4 || c.animation != nil {
+		t.Errorf("expected the completion callback to place the tile, found %s", FormatCell(*c))
+	}
+}
+
+func TestCancelSkipsCallbacks(t *testing.T) {
+	called := false
+	anim := Parallel(Delay(time.Millisecond), Delay(2*time.Millisecond))
+	anim.OnComplete(func() { called = true })
+
+	if anim.GetLength() != 2*time.Millisecond {
+		t.Errorf("parallel should last as long as its longest child, found %v", anim.GetLength())
+	}
+
+	anim.Step(time.Millisecond)
+	anim.Cancel()
+	if anim.GetStatus() != ANIM_CANCELLED || anim.Step(time.Millisecond) == nil {
+		t.Errorf("a cancelled animation should not step any more")
+	}
+
+	if called {
+		t.Errorf("a cancelled animation should not run its callbacks")
+	}
+}
+
+func TestAnimationSpeed(t *testing.T) {
+	t.Cleanup(func() { ANIMATION_SPEED, REDUCED_MOTION = 1, false })
+
+	testCases := []struct {
+		name     string
+		speed    float64
+		reduced  bool
+		expected AnimationStatus
+	}{
+		{name: "normal", speed: 1, reduced: false, expected: ANIM_RUNNING},
+		{name: "twice as fast", speed: 2, reduced: false, expected: ANIM_FINISHED},
+		{name: "reduced motion", speed: 1, reduced: true, expected: ANIM_FINISHED},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ANIMATION_SPEED, REDUCED_MOTION = tc.speed, tc.reduced
+
+			g := &Game{board: Board{cells: [][]Cell{{m(2)}}}}
+			anim := Delay(MAX_ANIMATION_STEP * 3 / 2)
+			g.board.cells[0][0].animation = anim
+			g.lastTick = time.Now().Add(-time.Hour)
+
+			AdvanceAnimations(g)
+			if anim.GetStatus() != tc.expected {
+				t.Errorf("expected status %v, found %v", tc.expected, anim.GetStatus())
+			}
+		})
+	}
+}
+
+func TestParallelSkipsCancelled(t *testing.T) {
+	short, cancelled, long := Delay(10*time.Millisecond), Delay(10*time.Millisecond), Delay(30*time.Millisecond)
+	p := Parallel(short, cancelled, long)
+	cancelled.Cancel()
+
+	testCases := []struct {
+		name     string
+		dt       time.Duration
+		expected AnimationStatus
+	}{
+		{name: "short one finishes", dt: 10 * time.Millisecond, expected: ANIM_RUNNING},
+		{name: "long one finishes", dt: 20 * time.Millisecond, expected: ANIM_FINISHED},
+	}
+
+	for _, tc := range testCases {
+		if err := p.Step(tc.dt); err != nil {
+			t.Fatalf("%s: expected the cancelled child to be skipped, found %v", tc.name, err)
+		}
+
+		if actual := p.GetStatus(); actual != tc.expected {
+			t.Errorf("%s: expected status %d, found %d", tc.name, tc.expected, actual)
+		}
+	}
+}
+
+func TestSequenceCarriesLeftover(t *testing.T) {
+	first, cancelled, second, third := Delay(10*time.Millisecond), Delay(10*time.Millisecond), Delay(10*time.Millisecond), Delay(10*time.Millisecond)
+	s := Sequence(first, cancelled, second, third)
+	cancelled.Cancel()
+
+	testCases := []struct {
+		name            string
+		dt              time.Duration
+		expectedCurrent Animation
+		expectedElapsed time.Duration
+	}{
+		{name: "into the second child", dt: 15 * time.Millisecond, expectedCurrent: second, expectedElapsed: 5 * time.Millisecond},
+		{name: "past two children at once", dt: 10 * time.Millisecond, expectedCurrent: third, expectedElapsed: 5 * time.Millisecond},
+	}
+
+	for _, tc := range testCases {
+		if err := s.Step(tc.dt); err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+
+		current := s.children[s.current]
+		if current != tc.expectedCurrent || current.(*DelayAnimation).elapsed != tc.expectedElapsed {
+			t.Errorf("%s: expected child %d to be %v in, found child %d %v in", tc.name, slices.Index(s.children, tc.expectedCurrent), tc.expectedElapsed, s.current, current.(*DelayAnimation).elapsed)
+		}
+	}
+
+	// 40ms of children with one cancelled take 30ms
+	if err := s.Step(5 * time.Millisecond); err != nil || s.GetStatus() != ANIM_FINISHED {
+		t.Errorf("expected the sequence to finish after 30ms, found status %d and %v", s.GetStatus(), err)
+	}
+}
@@ -0,0 +1,216 @@
+package game
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// How long a new screen takes to fade in
+var TRANSITION_DURATION = time.Second / 5
+
+// Screens drawn on top of the screen below them, e.g. the pause menu or a confirm dialog
+// Overlays never change the layout, the screen below keeps its board where it is
+type Overlay interface {
+	IsOverlay() bool
+}
+
+// Screens that read raw key presses, e.g. to rebind a key
+// While CapturesKeys is true the app wide keys are left to the screen
+type KeyCapturer interface {
+	CapturesKeys() bool
+}
+
+// Screens that have to keep something before the window closes, e.g. a game in progress
+type Closer interface {
+	OnClose()
+}
+
+// App is the top level ebiten.Game
+// It keeps a stack of screens, only the top one is updated and overlays are drawn over the ones below
+// The screen is drawn in device pixels, screens that implement Resizer are told whenever the size changes
+type App struct {
+	scenes   []ebiten.Game
+	laidOut  ebiten.Game
+	width    int
+	height   int
+	fade     *DelayAnimation
+	lastTick time.Time
+}
+
+func NewApp() *App {
+	return &App{}
+}
+
+func isOverlay(screen ebiten.Game) bool {
+	o, ok := screen.(Overlay)
+	return ok && o.IsOverlay()
+}
+
+func capturesKeys(screen ebiten.Game) bool {
+	c, ok := screen.(KeyCapturer)
+	return ok && c.CapturesKeys()
+}
+
+// Replaces the top screen, or starts the stack with screen
+func (a *App) SetScreen(screen ebiten.Game) {
+	if len(a.scenes) == 0 {
+		a.Push(screen)
+		return
+	}
+
+	a.scenes[len(a.scenes)-1] = screen
+	a.startTransition(screen)
+}
+
+// Opens screen on top of the current one, Pop comes back
+func (a *App) Push(screen ebiten.Game) {
+	a.scenes = append(a.scenes, screen)
+	a.startTransition(screen)
+}
+
+// Closes the top screen, the app quits once the last one is closed
+func (a *App) Pop() {
+	if len(a.scenes) == 0 {
+		return
+	}
+
+	top := a.scenes[len(a.scenes)-1]
+	a.scenes = a.scenes[:len(a.scenes)-1]
+	if len(a.scenes) > 0 && !isOverlay(top) {
+		a.startTransition(a.Top())
+	}
+}
+
+// Closes every screen above the bottom one, e.g. to get back to the main menu
+func (a *App) PopToRoot() {
+	if len(a.scenes) > 1 {
+		a.scenes = a.scenes[:1]
+		a.startTransition(a.Top())
+	}
+}
+
+// Lets every screen keep what it has to, top first, the app quits with the next Update
+func (a *App) Close() {
+	for i := len(a.scenes) - 1; i >= 0; i-- {
+		if c, ok := a.scenes[i].(Closer); ok {
+			c.OnClose()
+		}
+	}
+
+	a.scenes = nil
+}
+
+func (a *App) Top() ebiten.Game {
+	if len(a.scenes) == 0 {
+		return nil
+	}
+
+	return a.scenes[len(a.scenes)-1]
+}
+
+// Screens fade in unless they are drawn over another one
+func (a *App) startTransition(screen ebiten.Game) {
+	if isOverlay(screen) || TRANSITION_DURATION <= 0 || REDUCED_MOTION {
+		return
+	}
+
+	a.fade = Delay(TRANSITION_DURATION)
+	a.fade.easing = EaseOutQuad
+}
+
+// Topmost screen that is not an overlay, the one that decides the layout
+func (a *App) base() int {
+	i := len(a.scenes) - 1
+	for i > 0 && isOverlay(a.scenes[i]) {
+		i--
+	}
+
+	return i
+}
+
+func (a *App) Update() error {
+	if len(a.scenes) == 0 {
+		return errors.New("SIGKILL")
+	}
+
+	// main asks ebiten to leave closing the window to the app
+	if ebiten.IsWindowBeingClosed() {
+		a.Close()
+		return errors.New("SIGKILL")
+	}
+
+	now := time.Now()
+	if a.fade != nil {
+		dt := time.Duration(0)
+		if !a.lastTick.IsZero() {
+			dt = min(now.Sub(a.lastTick), MAX_ANIMATION_STEP)
+		}
+
+		a.fade.Step(dt)
+		if a.fade.GetStatus() == ANIM_FINISHED {
+			a.fade = nil
+		}
+	}
+	a.lastTick = now
+
+	// Themes switch on the fly on every screen, only the font has to be reloaded
+	if Bindings().JustPressed(ACTION_THEME) && !capturesKeys(a.Top()) && CycleTheme() {
+		Fonts().Reload()
+	}
+
+	if err := a.Top().Update(); err != nil {
+		return err
+	}
+
+	if len(a.scenes) == 0 {
+		return errors.New("SIGKILL")
+	}
+
+	return nil
+}
+
+func (a *App) Draw(screen *ebiten.Image) {
+	if len(a.scenes) == 0 {
+		return
+	}
+
+	for _, scene := range a.scenes[a.base():] {
+		scene.Draw(screen)
+	}
+
+	if a.fade != nil {
+		alpha := uint8(0xff * (1 - a.fade.Progress()))
+		b := screen.Bounds()
+		vector.DrawFilledRect(screen, 0, 0, float32(b.Dx()), float32(b.Dy()), color.NRGBA{0, 0, 0, alpha}, false)
+	}
+}
+
+func (a *App) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
+	w, h := ScaledSize(outsideWidth, outsideHeight)
+	if len(a.scenes) == 0 {
+		return w, h
+	}
+
+	base := a.scenes[a.base()]
+	if w == a.width && h == a.height && base == a.laidOut {
+		return w, h
+	}
+
+	a.width, a.height, a.laidOut = w, h, base
+	screenSize = Vec2{x: w, y: h}
+
+	// Screens without a board of their own still get cells and fonts that fit the screen
+	if r, ok := base.(Resizer); ok {
+		r.Resize(w, h)
+	} else {
+		area := image.Rect(0, 0, w, h)
+		SetCellSize(FitCellSize(LayoutSpec{boxes: 1}, CELL_COUNT, area, OrientationOf(area)))
+	}
+
+	return w, h
+}
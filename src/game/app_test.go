@@ -0,0 +1,136 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+type fakeScene struct {
+	overlay bool
+	updates int
+	draws   int
+	closed  int
+}
+
+func (s *fakeScene) Update() error              { s.updates++; return nil }
+func (s *fakeScene) Draw(screen *ebiten.Image)  { s.draws++ }
+func (s *fakeScene) Layout(w, h int) (int, int) { return w, h }
+func (s *fakeScene) IsOverlay() bool            { return s.overlay }
+func (s *fakeScene) OnClose()                   { s.closed++ }
+
+func TestSceneStack(t *testing.T) {
+	app := NewApp()
+	menu, game, pause := &fakeScene{}, &fakeScene{}, &fakeScene{overlay: true}
+
+	app.SetScreen(menu)
+	app.Push(game)
+	app.Push(pause)
+
+	if err := app.Update(); err != nil {
+		t.Fatal(err)
+	}
+
+	if pause.updates != 1 || game.updates != 0 || menu.updates != 0 {
+		t.Errorf("expected only the top screen to be updated, found %d %d %d", menu.updates, game.updates, pause.updates)
+	}
+
+	app.Draw(ebiten.NewImage(4, 4))
+	if pause.draws != 1 || game.draws != 1 || menu.draws != 0 {
+		t.Errorf("expected the overlay and the screen below it to be drawn, found %d %d %d", menu.draws, game.draws, pause.draws)
+	}
+
+	app.Pop()
+	if app.Top() != game {
+		t.Error("expected the game back on top")
+	}
+
+	app.PopToRoot()
+	if app.Top() != menu {
+		t.Error("expected the menu at the root")
+	}
+
+	app.Pop()
+	if err := app.Update(); err == nil {
+		t.Error("expected the app to quit once the last screen is closed")
+	}
+}
+
+func TestAppClose(t *testing.T) {
+	app := NewApp()
+	menu, game := &fakeScene{}, &fakeScene{}
+	app.SetScreen(menu)
+	app.Push(game)
+
+	app.Close()
+	if menu.closed != 1 || game.closed != 1 {
+		t.Errorf("expected every screen to be closed once, found %d %d", menu.closed, game.closed)
+	}
+
+	if err := app.Update(); err == nil {
+		t.Error("expected the app to quit once it is closed")
+	}
+}
+
+func TestSceneTransition(t *testing.T) {
+	app := NewApp()
+	app.SetScreen(&fakeScene{})
+	if app.fade == nil {
+		t.Fatal("expected a new screen to fade in")
+	}
+
+	app.fade.Step(TRANSITION_DURATION)
+	app.fade = nil
+
+	app.Push(&fakeScene{overlay: true})
+	if app.fade != nil {
+		t.Error("expected overlays to show up at once")
+	}
+}
+
+func TestPauseGame(t *testing.T) {
+	g := &Game{board: NewBoardAt(4, 0, 0), spawner: &ScriptedSpawner{spawns: []Spawn{{pos_x: 0, pos_y: 0, val: 2}}}}
+	SpawnCell(g)
+	g.lastTick = time.Now().Add(-time.Second)
+
+	PauseGame(g)
+	if g.status != PAUSED {
+		t.Fatalf("expected the game to be paused, found %d", g.status)
+	}
+
+	ResumeGame(g)
+	if g.status != RUNNING || !g.lastTick.IsZero() {
+		t.Fatal("expected the game to run again without the paused time")
+	}
+
+	AdvanceAnimations(g)
+	if !HasRunningAnimation(g) {
+		t.Error("expected the spawn animation to go on where it stopped")
+	}
+}
+
+func TestInProgress(t *testing.T) {
+	testCases := []struct {
+		name     string
+		status   GameStatus
+		unpaused GameStatus
+		moves    int
+		expected bool
+	}{
+		{name: "no moves yet", status: RUNNING, moves: 0, expected: false},
+		{name: "running", status: RUNNING, moves: 3, expected: true},
+		{name: "paused", status: PAUSED, unpaused: RUNNING, moves: 3, expected: true},
+		{name: "paused after the end", status: PAUSED, unpaused: GAME_OVER, moves: 3, expected: false},
+		{name: "over", status: GAME_OVER, moves: 3, expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := &Game{status: tc.status, unpaused: tc.unpaused, moves: tc.moves}
+			if actual := inProgress(g); actual != tc.expected {
+				t.Errorf("expected %t, found %t", tc.expected, actual)
+			}
+		})
+	}
+}
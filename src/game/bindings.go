@@ -0,0 +1,245 @@
+package game
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+type Action int32
+
+const (
+	ACTION_UP Action = iota
+	ACTION_RIGHT
+	ACTION_DOWN
+	ACTION_LEFT
+	ACTION_UNDO
+	ACTION_REDO
+	ACTION_RESTART
+	ACTION_HINT
+	ACTION_PAUSE
+	ACTION_QUIT
+	ACTION_MUTE
+	ACTION_SCREENSHOT
+	// Works on every screen, see App.Update
+	ACTION_THEME
+)
+
+// Every action in the order the rebinding screen lists them
+var ACTIONS = []Action{ACTION_UP, ACTION_RIGHT, ACTION_DOWN, ACTION_LEFT, ACTION_UNDO, ACTION_REDO, ACTION_RESTART, ACTION_HINT, ACTION_PAUSE, ACTION_QUIT, ACTION_MUTE, ACTION_SCREENSHOT, ACTION_THEME}
+
+// Names of the actions in the settings file
+var ACTION_NAMES = map[Action]string{
+	ACTION_UP:         "up",
+	ACTION_RIGHT:      "right",
+	ACTION_DOWN:       "down",
+	ACTION_LEFT:       "left",
+	ACTION_UNDO:       "undo",
+	ACTION_REDO:       "redo",
+	ACTION_RESTART:    "restart",
+	ACTION_HINT:       "hint",
+	ACTION_PAUSE:      "pause",
+	ACTION_QUIT:       "quit",
+	ACTION_MUTE:       "mute",
+	ACTION_SCREENSHOT: "screenshot",
+	ACTION_THEME:      "theme",
+}
+
+// Keys that trigger each action, any of them will do
+type KeyBindings map[Action][]ebiten.Key
+
+// Names of the presets in the order the rebinding screen cycles through them
+var KEY_PRESET_NAMES = []string{"arrows", "wasd", "hjkl"}
+
+var KEY_PRESETS = map[string]KeyBindings{
+	"arrows": {
+		ACTION_UP:         {ebiten.KeyArrowUp},
+		ACTION_RIGHT:      {ebiten.KeyArrowRight},
+		ACTION_DOWN:       {ebiten.KeyArrowDown},
+		ACTION_LEFT:       {ebiten.KeyArrowLeft},
+		ACTION_UNDO:       {ebiten.KeyZ, ebiten.KeyBackspace},
+		ACTION_REDO:       {ebiten.KeyY},
+		ACTION_RESTART:    {ebiten.KeyR},
+		ACTION_HINT:       {ebiten.KeyH},
+		ACTION_PAUSE:      {ebiten.KeyP},
+		ACTION_QUIT:       {ebiten.KeyEscape},
+		ACTION_MUTE:       {ebiten.KeyM},
+		ACTION_SCREENSHOT: {ebiten.KeyF12},
+		ACTION_THEME:      {ebiten.KeyT},
+	},
+	"wasd": {
+		ACTION_UP:         {ebiten.KeyW},
+		ACTION_RIGHT:      {ebiten.KeyD},
+		ACTION_DOWN:       {ebiten.KeyS},
+		ACTION_LEFT:       {ebiten.KeyA},
+		ACTION_UNDO:       {ebiten.KeyZ, ebiten.KeyBackspace},
+		ACTION_REDO:       {ebiten.KeyY},
+		ACTION_RESTART:    {ebiten.KeyR},
+		ACTION_HINT:       {ebiten.KeyH},
+		ACTION_PAUSE:      {ebiten.KeyP},
+		ACTION_QUIT:       {ebiten.KeyEscape},
+		ACTION_MUTE:       {ebiten.KeyM},
+		ACTION_SCREENSHOT: {ebiten.KeyF12},
+		ACTION_THEME:      {ebiten.KeyT},
+	},
+	"hjkl": {
+		ACTION_UP:         {ebiten.KeyK},
+		ACTION_RIGHT:      {ebiten.KeyL},
+		ACTION_DOWN:       {ebiten.KeyJ},
+		ACTION_LEFT:       {ebiten.KeyH},
+		ACTION_UNDO:       {ebiten.KeyU},
+		ACTION_REDO:       {ebiten.KeyY},
+		ACTION_RESTART:    {ebiten.KeyR},
+		ACTION_HINT:       {ebiten.KeyI},
+		ACTION_PAUSE:      {ebiten.KeyP},
+		ACTION_QUIT:       {ebiten.KeyEscape},
+		ACTION_MUTE:       {ebiten.KeyM},
+		ACTION_SCREENSHOT: {ebiten.KeyF12},
+		ACTION_THEME:      {ebiten.KeyT},
+	},
+}
+
+var ARROW_KEYS = KEY_PRESETS["arrows"]
+var WASD_KEYS = KEY_PRESETS["wasd"]
+
+var keyBindings = ARROW_KEYS.Clone()
+
+// Bindings of the player, used by every single player screen
+func Bindings() KeyBindings {
+	return keyBindings
+}
+
+func SetBindings(kb KeyBindings) {
+	keyBindings = kb
+}
+
+// Builds the bindings of a preset with the keys of some actions replaced
+// overrides maps action names to key names as written by ebiten.Key.String, e.g. "ArrowUp" or "Q"
+func NewKeyBindings(preset string, overrides map[string][]string) (KeyBindings, error) {
+	if preset == "" {
+		preset = KEY_PRESET_NAMES[0]
+	}
+
+	base, ok := KEY_PRESETS[preset]
+	if !ok {
+		return nil, fmt.Errorf("unknown key preset %q, expected one of %s", preset, strings.Join(KEY_PRESET_NAMES, ", "))
+	}
+
+	kb := base.Clone()
+	for name, keyNames := range overrides {
+		action, ok := ActionByName(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown action %q", name)
+		}
+
+		keys := make([]ebiten.Key, 0, len(keyNames))
+		for _, keyName := range keyNames {
+			var key ebiten.Key
+			if err := key.UnmarshalText([]byte(keyName)); err != nil {
+				return nil, fmt.Errorf("%s: unknown key %q", name, keyName)
+			}
+			keys = append(keys, key)
+		}
+
+		kb[action] = keys
+	}
+
+	return kb, nil
+}
+
+func ActionByName(name string) (Action, bool) {
+	for action, n := range ACTION_NAMES {
+		if n == name {
+			return action, true
+		}
+	}
+
+	return ACTION_UP, false
+}
+
+// impl KeyBindings
+
+func (kb KeyBindings) Clone() KeyBindings {
+	cloned := make(KeyBindings, len(kb))
+	for action, keys := range kb {
+		cloned[action] = slices.Clone(keys)
+	}
+
+	return cloned
+}
+
+func (kb KeyBindings) JustPressed(action Action) bool {
+	for _, key := range kb[action] {
+		if inpututil.IsKeyJustPressed(key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Actions of every key that is bound more than once
+func (kb KeyBindings) Conflicts() map[ebiten.Key][]Action {
+	seen := map[ebiten.Key][]Action{}
+	for _, action := range ACTIONS {
+		for _, key := range kb[action] {
+			seen[key] = append(seen[key], action)
+		}
+	}
+
+	conflicts := map[ebiten.Key][]Action{}
+	for key, actions := range seen {
+		if len(actions) > 1 {
+			conflicts[key] = actions
+		}
+	}
+
+	return conflicts
+}
+
+// Keys of the actions that differ from preset, keyed by action name for the settings file
+func (kb KeyBindings) Overrides(preset string) map[string][]string {
+	base := KEY_PRESETS[preset]
+	overrides := map[string][]string{}
+
+	for _, action := range ACTIONS {
+		if slices.Equal(kb[action], base[action]) {
+			continue
+		}
+
+		names := make([]string, len(kb[action]))
+		for i, key := range kb[action] {
+			names[i] = key.String()
+		}
+		overrides[ACTION_NAMES[action]] = names
+	}
+
+	return overrides
+}
+
+// Readable list of the keys of an action, e.g. "Z / Backspace"
+func (kb KeyBindings) Label(action Action) string {
+	names := make([]string, len(kb[action]))
+	for i, key := range kb[action] {
+		names[i] = key.String()
+	}
+
+	return strings.Join(names, " / ")
+}
+
+// First key of every move in the order up, left, down, right, e.g. "W A S D"
+func (kb KeyBindings) MoveLabel() string {
+	names := []string{}
+	for _, action := range []Action{ACTION_UP, ACTION_LEFT, ACTION_DOWN, ACTION_RIGHT} {
+		if len(kb[action]) > 0 {
+			names = append(names, kb[action][0].String())
+		}
+	}
+
+	return strings.Join(names, " ")
+}
+
+// end
@@ -0,0 +1,114 @@
+package game
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+func TestNewKeyBindings(t *testing.T) {
+	testCases := []struct {
+		name        string
+		preset      string
+		overrides   map[string][]string
+		action      Action
+		expected    []ebiten.Key
+		expectedErr bool
+	}{
+		{name: "default preset", preset: "", action: ACTION_UP, expected: []ebiten.Key{ebiten.KeyArrowUp}},
+		{name: "wasd", preset: "wasd", action: ACTION_LEFT, expected: []ebiten.Key{ebiten.KeyA}},
+		{name: "hjkl", preset: "hjkl", action: ACTION_DOWN, expected: []ebiten.Key{ebiten.KeyJ}},
+		{name: "override", preset: "arrows", overrides: map[string][]string{"undo": {"U", "Backspace"}}, action: ACTION_UNDO, expected: []ebiten.Key{ebiten.KeyU, ebiten.KeyBackspace}},
+		{name: "override keeps the rest", preset: "arrows", overrides: map[string][]string{"undo": {"U"}}, action: ACTION_QUIT, expected: []ebiten.Key{ebiten.KeyEscape}},
+		{name: "unknown preset", preset: "emacs", expectedErr: true},
+		{name: "unknown action", preset: "arrows", overrides: map[string][]string{"jump": {"Space"}}, expectedErr: true},
+		{name: "unknown key", preset: "arrows", overrides: map[string][]string{"undo": {"Hyper"}}, expectedErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			kb, err := NewKeyBindings(tc.preset, tc.overrides)
+			if tc.expectedErr {
+				if err == nil {
+					t.Error("expected an error")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !slices.Equal(kb[tc.action], tc.expected) {
+				t.Errorf("expected %v, found %v", tc.expected, kb[tc.action])
+			}
+		})
+	}
+}
+
+func TestBindingOverridesRoundTrip(t *testing.T) {
+	kb := KEY_PRESETS["wasd"].Clone()
+	kb[ACTION_HINT] = []ebiten.Key{ebiten.KeyQ}
+
+	overrides := kb.Overrides("wasd")
+	if len(overrides) != 1 || !slices.Equal(overrides["hint"], []string{"Q"}) {
+		t.Fatalf("expected only the hint to be overridden, found %v", overrides)
+	}
+
+	loaded, err := NewKeyBindings("wasd", overrides)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, action := range ACTIONS {
+		if !slices.Equal(loaded[action], kb[action]) {
+			t.Errorf("%s: expected %v, found %v", ACTION_NAMES[action], kb[action], loaded[action])
+		}
+	}
+}
+
+func TestBindingConflicts(t *testing.T) {
+	for name, preset := range KEY_PRESETS {
+		if conflicts := preset.Conflicts(); len(conflicts) > 0 {
+			t.Errorf("preset %s binds keys twice: %v", name, conflicts)
+		}
+
+		for _, action := range ACTIONS {
+			if len(preset[action]) == 0 {
+				t.Errorf("preset %s leaves %s unbound", name, ACTION_NAMES[action])
+			}
+		}
+	}
+
+	kb := ARROW_KEYS.Clone()
+	kb[ACTION_HINT] = []ebiten.Key{ebiten.KeyR}
+	if actions := kb.Conflicts()[ebiten.KeyR]; len(actions) != 2 {
+		t.Errorf("expected R to be bound to restart and hint, found %v", actions)
+	}
+
+	// Switching themes is an action like any other, taking its key is a conflict too
+	kb[ACTION_UNDO] = []ebiten.Key{ebiten.KeyT}
+	if actions := kb.Conflicts()[ebiten.KeyT]; !slices.Equal(actions, []Action{ACTION_UNDO, ACTION_THEME}) {
+		t.Errorf("expected T to be bound to undo and theme, found %v", actions)
+	}
+}
+
+func TestMoveLabel(t *testing.T) {
+	testCases := []struct {
+		preset   string
+		expected string
+	}{
+		{preset: "arrows", expected: "ArrowUp ArrowLeft ArrowDown ArrowRight"},
+		{preset: "wasd", expected: "W A S D"},
+		{preset: "hjkl", expected: "K H J L"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.preset, func(t *testing.T) {
+			if actual := KEY_PRESETS[tc.preset].MoveLabel(); actual != tc.expected {
+				t.Errorf("expected %q, found %q", tc.expected, actual)
+			}
+		})
+	}
+}
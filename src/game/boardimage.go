@@ -0,0 +1,32 @@
+package game
+
+import (
+	"image"
+	"time"
+
+	"mkoca/2048/src/replay"
+)
+
+// Cell size of board images in pixels
+var IMAGE_CELL_SIZE = 100
+
+// Screenshots are saved here, relative to the working directory
+var SCREENSHOT_DIR = "screenshots"
+
+// How board images look in the theme
+func ImageStyle(t *Theme) replay.Style {
+	return t.style
+}
+
+// The board of the game in the current theme
+// Running animations are finished first, a tile still spawning only gets its value at the end
+func BoardImage(g *Game) *image.RGBA {
+	FinishAnimations(g)
+	style := ImageStyle(CurrentTheme())
+	return replay.RenderBoard(cellValues(g.board.cells), g.score, &style, IMAGE_CELL_SIZE)
+}
+
+// Saves the board of the game into SCREENSHOT_DIR
+func Screenshot(g *Game) (string, error) {
+	return replay.SavePNG(BoardImage(g), SCREENSHOT_DIR, time.Now())
+}
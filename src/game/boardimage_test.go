@@ -0,0 +1,82 @@
+package game
+
+import (
+	"image/color"
+	"image/png"
+	"os"
+	"testing"
+)
+
+func TestImageStyle(t *testing.T) {
+	theme := ClassicTheme()
+	theme.style.CornerRadius = 0.25
+	style := ImageStyle(theme)
+
+	testCases := []struct {
+		name     string
+		actual   any
+		expected any
+	}{
+		{name: "board", actual: style.Board, expected: theme.style.Board},
+		{name: "tile", actual: style.Tile(8).Fill, expected: theme.Tile(8).Fill},
+		{name: "fallback", actual: style.Tile(1 << 20).Text, expected: theme.Tile(1 << 20).Text},
+		{name: "highlight", actual: style.Highlight, expected: theme.Tile(2048).Fill},
+		{name: "info text", actual: style.InfoText, expected: ReadableTextColor(theme.style.InfoBox)},
+		{name: "corner radius", actual: style.Radius(IMAGE_CELL_SIZE), expected: float64(IMAGE_CELL_SIZE) / 4},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.actual != tc.expected {
+				t.Errorf("expected %v, found %v", tc.expected, tc.actual)
+			}
+		})
+	}
+}
+
+func TestBoardImage(t *testing.T) {
+	g := mustParseNotation(t, "1.../..../..../....").NewGame()
+	img := BoardImage(g)
+
+	// The first cell sits one gap in from the corner of the board, below the score box
+	gap := max(1, IMAGE_CELL_SIZE/12)
+	x, y := gap+IMAGE_CELL_SIZE/10, IMAGE_CELL_SIZE*3/4+gap+IMAGE_CELL_SIZE/10
+	expected := color.NRGBAModel.Convert(CurrentTheme().Tile(2).Fill)
+	if actual := color.NRGBAModel.Convert(img.At(x, y)); actual != expected {
+		t.Errorf("expected the 2 tile at %d,%d, found %v", x, y, actual)
+	}
+}
+
+func TestScreenshotRightAfterMove(t *testing.T) {
+	defer func(dir string) { SCREENSHOT_DIR = dir }(SCREENSHOT_DIR)
+	SCREENSHOT_DIR = t.TempDir()
+
+	g := mustParseNotation(t, "11../..../..../....").NewGame()
+	g.spawner = &ScriptedSpawner{spawns: []Spawn{{pos_x: 3, pos_y: 3, val: 4}}}
+	PlayMove(g, LEFT)
+
+	path, err := Screenshot(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	img, err := png.Decode(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The bottom right cell of a 4x4 board, its 4 was still spawning when the screenshot was taken
+	gap := max(1, IMAGE_CELL_SIZE/12)
+	x := gap + 3*(IMAGE_CELL_SIZE+gap) + IMAGE_CELL_SIZE/10
+	y := IMAGE_CELL_SIZE*3/4 + x
+	expected := color.NRGBAModel.Convert(CurrentTheme().Tile(4).Fill)
+	if actual := color.NRGBAModel.Convert(img.At(x, y)); actual != expected {
+		t.Errorf("expected the spawned 4 at %d,%d, found %v", x, y, actual)
+	}
+}
@@ -0,0 +1,266 @@
+package game
+
+import (
+	"errors"
+	"image"
+	"log"
+
+	"mkoca/2048/src/replay"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// The classic mode, one board with undo, hints and a pause menu
+// New game and undo buttons sit under the scoreboard
+type ClassicGame struct {
+	app     *App
+	game    *Game
+	buttons *UI
+	// Whether the end of the current game went into the stats already
+	recorded bool
+	// Shown below the board until the next move
+	notice      string
+	noticeMoves int
+}
+
+func NewClassicGame(app *App, g *Game) *ClassicGame {
+	// The score and the buttons, hints and notices go below the board
+	g.layout.spec = LayoutSpec{boxes: 2, footerLines: 1}
+	return &ClassicGame{app: app, game: g, buttons: NewPointerUI()}
+}
+
+// A game with moves that has not ended yet, paused games count too
+func inProgress(g *Game) bool {
+	status := g.status
+	if status == PAUSED {
+		status = g.unpaused
+	}
+
+	return status == RUNNING && g.moves > 0
+}
+
+// Freezes the game and opens the pause menu over it
+func (cg *ClassicGame) pause() {
+	PauseGame(cg.game)
+	cg.app.Push(NewPauseMenu(cg.app, cg.game, cg.restart, cg.quit))
+}
+
+// Starts over with the board size and spawn settings that are current now
+func (cg *ClassicGame) restart() {
+	g := cg.game
+	if inProgress(g) {
+		saveLastGame(g)
+	}
+
+	if len(g.board.cells) != CELL_COUNT {
+		ResetBoard(&g.board)
+		g.board = NewBoard(CELL_COUNT)
+		Relayout(g)
+	}
+
+	if spawner, ok := g.spawner.(*RandomSpawner); ok {
+		spawner.fourChance = FOUR_CHANCE
+	}
+
+	ResetGame(g)
+	cg.recorded = false
+}
+
+// Keeps the recording of the game for exporting it later, see replay.LAST_GAME_FILE
+func saveLastGame(g *Game) {
+	path, err := ConfigPath(replay.LAST_GAME_FILE)
+	if err == nil {
+		err = RecordGame(g).Save(path)
+	}
+
+	if err != nil {
+		log.Println("failed to save the recording:", err)
+	}
+}
+
+// Starts over, asking first when that abandons a game in progress
+func (cg *ClassicGame) newGame() {
+	if !inProgress(cg.game) {
+		cg.restart()
+		return
+	}
+
+	cg.app.Push(NewConfirmDialog(cg.app, "Abandon this game?", cg.restart))
+}
+
+// A won or lost game is in the stats already, taking moves back would let its real ending go unrecorded
+func (cg *ClassicGame) undo() {
+	if cg.recorded {
+		return
+	}
+
+	Undo(cg.game)
+	cg.game.showHint = false
+}
+
+func (cg *ClassicGame) screenshot() {
+	// The notation goes into the log too so bug reports can paste the exact position
+	path, err := Screenshot(cg.game)
+	if err != nil {
+		log.Println("failed to save the screenshot:", err)
+		cg.notice = "Failed to save the screenshot"
+	} else {
+		log.Printf("saved %s, board %s", path, NotationOf(cg.game))
+		cg.notice = "Saved " + path
+	}
+
+	cg.noticeMoves = cg.game.moves
+}
+
+// Asks before leaving a game that is still going on
+func (cg *ClassicGame) leave() {
+	if !inProgress(cg.game) {
+		cg.app.Pop()
+		return
+	}
+
+	cg.app.Push(NewConfirmDialog(cg.app, "Leave this game?", func() {
+		saveLastGame(cg.game)
+		cg.app.Pop()
+	}))
+}
+
+// Back to the main menu from the pause menu, the player confirmed it already
+func (cg *ClassicGame) quit() {
+	if inProgress(cg.game) {
+		saveLastGame(cg.game)
+	}
+
+	cg.app.PopToRoot()
+}
+
+// Keeps the game in progress when the window is closed
+func (cg *ClassicGame) OnClose() {
+	if inProgress(cg.game) {
+		saveLastGame(cg.game)
+	}
+}
+
+// Adds the finished game to the stats once
+func (cg *ClassicGame) record() {
+	if cg.recorded {
+		return
+	}
+	cg.recorded = true
+	saveLastGame(cg.game)
+
+	stats, err := LoadStats()
+	if err != nil {
+		log.Println("stats are not available:", err)
+	}
+
+	stats.Record(cg.game)
+	if err := stats.Save(); err != nil {
+		log.Println("failed to save the stats:", err)
+	}
+}
+
+func (cg *ClassicGame) Update() error {
+	g := cg.game
+	AssignGamepads([]*Game{g})
+
+	if GameKeys(g).JustPressed(ACTION_QUIT) {
+		cg.leave()
+		return nil
+	}
+
+	if GameKeys(g).JustPressed(ACTION_PAUSE) || g.gamepad.JustPressed(PAD_MENU) {
+		cg.pause()
+		return nil
+	}
+
+	AdvanceAnimations(g)
+
+	ui := cg.buttons
+	ui.Begin(InfoBoxRect(g, 1))
+	newGame := ui.Button("New game")
+	undo := ui.Button("Undo")
+	ui.End()
+
+	if newGame || GameKeys(g).JustPressed(ACTION_RESTART) || g.gamepad.JustPressed(PAD_NEW_GAME) {
+		cg.newGame()
+		return nil
+	}
+
+	if undo || GameKeys(g).JustPressed(ACTION_UNDO) || g.gamepad.JustPressed(PAD_UNDO) {
+		cg.undo()
+		return nil
+	}
+
+	if GameKeys(g).JustPressed(ACTION_REDO) {
+		Redo(g)
+		g.showHint = false
+		return nil
+	}
+
+	if GameKeys(g).JustPressed(ACTION_SCREENSHOT) {
+		cg.screenshot()
+	}
+
+	if GameKeys(g).JustPressed(ACTION_MUTE) {
+		ToggleMute()
+	}
+
+	if GameKeys(g).JustPressed(ACTION_HINT) && g.status == RUNNING {
+		g.hint, g.showHint = BestMove(g.board.cells)
+	}
+
+	switch g.status {
+	case RUNNING:
+		updateRunning(g)
+		if g.status != RUNNING {
+			cg.record()
+		}
+	case FINISHED, GAME_OVER:
+		// Only a deliberate key or tap starts over, moves pressed a moment too late must not
+		if inpututil.IsKeyJustPressed(ebiten.KeyEnter) || (AnyPointerJustPressed() && !ui.Hovered()) {
+			cg.restart()
+		}
+	case PAUSED:
+	default:
+		return errors.New("unhandled game status reached. exiting")
+	}
+
+	return nil
+}
+
+func (cg *ClassicGame) Draw(screen *ebiten.Image) {
+	g := cg.game
+	x, y := InfoBoxPosition(g, 0)
+
+	drawBackground(g, screen)
+	drawBoard(g, screen)
+	drawScoreboard(g, screen, x, y)
+	cg.buttons.Draw(screen)
+
+	restart := "Press Enter, " + GameKeys(g).Label(ACTION_RESTART) + " or tap to play again"
+	switch g.status {
+	case RUNNING:
+		switch {
+		case g.showHint:
+			drawFooterText(g, screen, "Hint: move "+g.hint.String())
+		case cg.notice != "" && g.moves == cg.noticeMoves:
+			drawFooterText(g, screen, cg.notice)
+		}
+	case FINISHED:
+		drawOverlay(g, screen)
+		drawAfterGameText(g, screen, "Congratulations!", restart)
+	case GAME_OVER:
+		drawOverlay(g, screen)
+		drawAfterGameText(g, screen, "Game Over!", restart)
+	}
+}
+
+func (cg *ClassicGame) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
+	return ScaledSize(outsideWidth, outsideHeight)
+}
+
+func (cg *ClassicGame) Resize(width int, height int) {
+	ApplyLayout(cg.game, image.Rect(0, 0, width, height))
+}
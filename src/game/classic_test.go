@@ -0,0 +1,30 @@
+package game
+
+import "testing"
+
+func TestUndoAfterRecord(t *testing.T) {
+	testCases := []struct {
+		name     string
+		recorded bool
+		expected int
+	}{
+		{name: "game in progress", recorded: false, expected: 0},
+		{name: "recorded game", recorded: true, expected: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := mustParseNotation(t, "11../..../..../....").NewGame()
+			cg := NewClassicGame(NewApp(), g)
+			PlayMove(g, LEFT)
+			FinishAnimations(g)
+
+			cg.recorded = tc.recorded
+			cg.undo()
+
+			if g.moves != tc.expected {
+				t.Errorf("expected %d moves after undo, found %d", tc.expected, g.moves)
+			}
+		})
+	}
+}
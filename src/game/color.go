@@ -1,9 +1,16 @@
 package game
 
-import "image/color"
+import (
+	"image/color"
+	"math"
+)
+
+// WCAG AA minimum contrast for normal text
+const MIN_TEXT_CONTRAST = 4.5
 
 var (
 	BEIGE       = color.NRGBA{0xfa, 0xf8, 0xef, 0xff}
+	BOARD_GRAY  = color.NRGBA{0x80, 0x80, 0x80, 0xff}
 	LIGHT_BROWN = color.NRGBA{0xcd, 0xc1, 0xb4, 0xff}
 
 	// Tile colors
@@ -22,6 +29,9 @@ var (
 	// For larger values
 	DARK_GRAY = color.NRGBA{0x3c, 0x3a, 0x32, 0xff}
 
+	// Cells that tiles can not enter
+	BLOCKED_CELL = color.NRGBA{0x1e, 0x1d, 0x19, 0xff}
+
 	// Text colors
 	TEXT_DARK  = color.NRGBA{0x77, 0x6e, 0x65, 0xff} // for 2, 4
 	TEXT_LIGHT = color.NRGBA{0xf9, 0xf6, 0xf2, 0xff} // for others
@@ -29,7 +39,7 @@ var (
 	OVERLAY_BACKGROUND = color.NRGBA{0xc6, 0xd0, 0xcf, 0x64}
 )
 
-// TileColors maps tile values to colors
+// TileColors maps tile values to colors of the classic theme
 var TileColors = map[int]color.NRGBA{
 	2:    LIGHT_TAN,
 	4:    TAN,
@@ -44,10 +54,65 @@ var TileColors = map[int]color.NRGBA{
 	2048: GOLD_DEEP,
 }
 
+// Fill colour of a tile value in the current theme
 func GetColor(val int) color.NRGBA {
-	if col, ok := TileColors[val]; ok {
-		return col
+	return CurrentTheme().Tile(val).Fill
+}
+
+// Text colour of a tile value in the current theme
+func GetTextColor(val int) color.NRGBA {
+	return CurrentTheme().Tile(val).Text
+}
+
+// Relative luminance as defined by WCAG 2
+func RelativeLuminance(c color.NRGBA) float64 {
+	channel := func(v uint8) float64 {
+		s := float64(v) / 0xff
+		if s <= 0.03928 {
+			return s / 12.92
+		}
+
+		return math.Pow((s+0.055)/1.055, 2.4)
+	}
+
+	return 0.2126*channel(c.R) + 0.7152*channel(c.G) + 0.0722*channel(c.B)
+}
+
+// WCAG contrast ratio between two colours, from 1 (same) to 21 (black on white)
+func ContrastRatio(a color.NRGBA, b color.NRGBA) float64 {
+	la, lb := RelativeLuminance(a), RelativeLuminance(b)
+	if la < lb {
+		la, lb = lb, la
+	}
+
+	return (la + 0.05) / (lb + 0.05)
+}
+
+// Picks TEXT_DARK or TEXT_LIGHT, whichever reads better on fill
+// Falls back to black or white when neither reaches MIN_TEXT_CONTRAST
+func ReadableTextColor(fill color.NRGBA) color.NRGBA {
+	best := TEXT_DARK
+	if ContrastRatio(TEXT_LIGHT, fill) > ContrastRatio(TEXT_DARK, fill) {
+		best = TEXT_LIGHT
+	}
+
+	if ContrastRatio(best, fill) >= MIN_TEXT_CONTRAST {
+		return best
+	}
+
+	black, white := color.NRGBA{0x00, 0x00, 0x00, 0xff}, color.NRGBA{0xff, 0xff, 0xff, 0xff}
+	if ContrastRatio(white, fill) > ContrastRatio(black, fill) {
+		return white
+	}
+
+	return black
+}
+
+// Mixes a towards b, t = 0 gives a and t = 1 gives b
+func Blend(a color.NRGBA, b color.NRGBA, t float64) color.NRGBA {
+	mix := func(x, y uint8) uint8 {
+		return uint8(float64(x) + (float64(y)-float64(x))*t + 0.5)
 	}
 
-	return DARK_GRAY
+	return color.NRGBA{mix(a.R, b.R), mix(a.G, b.G), mix(a.B, b.B), mix(a.A, b.A)}
 }
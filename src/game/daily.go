@@ -0,0 +1,304 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"image"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+var DAILY_HISTORY_FILE = "daily.json"
+var DAILY_SHARE_FILE = "daily.txt"
+
+// Everyone plays the daily challenge on the same board, whatever size they picked
+var DAILY_BOARD_SIZE = 4
+
+const (
+	DAILY_PLAYING  = "playing"
+	DAILY_FINISHED = "finished"
+	DAILY_OVER     = "game over"
+)
+
+// Outcome of one daily challenge
+// A result left in DAILY_PLAYING means the attempt was abandoned
+type DailyResult struct {
+	Date    string `json:"date"`
+	Status  string `json:"status"`
+	Score   int    `json:"score"`
+	MaxTile int    `json:"max_tile"`
+	Moves   int    `json:"moves"`
+	Summary string `json:"summary"`
+}
+
+type DailyHistory struct {
+	Results []DailyResult `json:"results"`
+}
+
+type DailyGame struct {
+	app     *App
+	date    string
+	game    *Game
+	history DailyHistory
+	// Why the history file could not be read, it is then left as it is instead of being overwritten
+	historyErr error
+	result     *DailyResult
+	// false when today's attempt was already used before this session
+	playing bool
+	message string
+}
+
+// Date key of the daily challenge, days roll over at midnight UTC
+func DailyDate(now time.Time) string {
+	return now.UTC().Format(time.DateOnly)
+}
+
+func DailySeed(date string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte("2048 daily " + date))
+	return h.Sum64()
+}
+
+// Spawner that produces the same sequence for everyone on the given date
+func NewDailySpawner(date string) *RandomSpawner {
+	return NewSeededSpawner(DailySeed(date))
+}
+
+// Shareable text for a finished daily, one square per cell
+func DailySummary(result DailyResult, cells [][]Cell) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "2048 daily %s\n", result.Date)
+	fmt.Fprintf(&sb, "Score %d, best tile %d, %d moves\n", result.Score, result.MaxTile, result.Moves)
+
+	for _, row := range cells {
+		for _, c := range row {
+			sb.WriteString(summarySquare(c))
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+func summarySquare(c Cell) string {
+	switch {
+	case !c.isRendered:
+		return "⬛"
+	case c.val <= 8:
+		return "🟨"
+	case c.val <= 64:
+		return "🟧"
+	case c.val <= 512:
+		return "🟥"
+	default:
+		return "🟪"
+	}
+}
+
+func LoadDailyHistory() (DailyHistory, error) {
+	var h DailyHistory
+
+	path, err := ConfigPath(DAILY_HISTORY_FILE)
+	if err != nil {
+		return h, err
+	}
+
+	err = readJSON(path, &h)
+	if errors.Is(err, os.ErrNotExist) {
+		return h, nil
+	}
+
+	return h, err
+}
+
+// impl DailyHistory
+
+func (h *DailyHistory) Find(date string) *DailyResult {
+	for i := range h.Results {
+		if h.Results[i].Date == date {
+			return &h.Results[i]
+		}
+	}
+
+	return nil
+}
+
+// Adds or replaces the result for its date
+func (h *DailyHistory) Record(result DailyResult) {
+	if r := h.Find(result.Date); r != nil {
+		*r = result
+		return
+	}
+
+	h.Results = append(h.Results, result)
+}
+
+func (h *DailyHistory) Save() error {
+	path, err := ConfigPath(DAILY_HISTORY_FILE)
+	if err != nil {
+		return err
+	}
+
+	return writeJSON(path, h)
+}
+
+// end
+
+// impl DailyGame
+
+func NewDailyGame(app *App) *DailyGame {
+	dg := DailyGame{app: app, date: DailyDate(time.Now())}
+
+	dg.history, dg.historyErr = LoadDailyHistory()
+	if dg.historyErr != nil {
+		log.Println("daily history is not available, results of this session are not saved:", dg.historyErr)
+	}
+
+	dg.game = NewGame(NewBoard(DAILY_BOARD_SIZE), NewDailySpawner(dg.date))
+	dg.game.layout.spec = LayoutSpec{boxes: 2, footerLines: 2}
+
+	if r := dg.history.Find(dg.date); r != nil {
+		// One attempt per day, show the stored result instead
+		dg.result = r
+		dg.game.status = GAME_OVER
+		return &dg
+	}
+
+	PlaceSpawn(dg.game)
+	dg.playing = true
+	dg.record(DAILY_PLAYING)
+
+	return &dg
+}
+
+func (dg *DailyGame) Update() error {
+	if Bindings().JustPressed(ACTION_QUIT) {
+		dg.app.Pop()
+		return nil
+	}
+
+	AdvanceAnimations(dg.game)
+	AssignGamepads([]*Game{dg.game})
+
+	if !dg.playing {
+		return nil
+	}
+
+	updateRunning(dg.game)
+
+	switch dg.game.status {
+	case FINISHED:
+		dg.finish(DAILY_FINISHED)
+	case GAME_OVER:
+		dg.finish(DAILY_OVER)
+	}
+
+	return nil
+}
+
+func (dg *DailyGame) Draw(screen *ebiten.Image) {
+	g := dg.game
+
+	drawBackground(g, screen)
+	drawBoard(g, screen)
+
+	x, y := InfoBoxPosition(g, 0)
+	drawInfoBox(g, screen, x, y, "DAILY", dg.date[5:])
+
+	x, y = InfoBoxPosition(g, 1)
+	if dg.playing {
+		drawScoreboard(g, screen, x, y)
+		return
+	}
+
+	drawInfoBox(g, screen, x, y, "SCORE", strconv.Itoa(dg.result.Score))
+	drawOverlay(g, screen)
+
+	title := "Daily complete!"
+	if dg.result.Status == DAILY_PLAYING {
+		title = "Daily abandoned"
+	}
+	drawAfterGameText(g, screen, title, "Come back tomorrow")
+
+	lines := []string{
+		fmt.Sprintf("Best tile %d in %d moves", dg.result.MaxTile, dg.result.Moves),
+		dg.message,
+	}
+
+	lh := int(Fonts().Face(FONT_CAPTION).Size * 1.5)
+	cx, top := FooterPosition(g)
+	for i, line := range lines {
+		txtOp := &text.DrawOptions{}
+		txtOp.ColorScale.ScaleWithColor(CurrentTheme().mutedText)
+		DrawCenteredText(screen, Fonts().Face(FONT_CAPTION), line, cx, top+lh*(i+1), txtOp)
+	}
+}
+
+func (dg *DailyGame) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
+	return ScaledSize(outsideWidth, outsideHeight)
+}
+
+func (dg *DailyGame) Resize(width int, height int) {
+	ApplyLayout(dg.game, image.Rect(0, 0, width, height))
+}
+
+// Stores the current state of today's attempt in the history
+func (dg *DailyGame) record(status string) {
+	g := dg.game
+	result := DailyResult{
+		Date:    dg.date,
+		Status:  status,
+		Score:   g.score,
+		MaxTile: MaxTile(g.board.cells),
+		Moves:   g.moves,
+	}
+
+	if status != DAILY_PLAYING {
+		result.Summary = DailySummary(result, g.board.cells)
+	}
+
+	dg.history.Record(result)
+	dg.result = dg.history.Find(dg.date)
+
+	// Saving over a file that could not be read would wipe the past results and the streak
+	if dg.historyErr != nil {
+		return
+	}
+
+	if err := dg.history.Save(); err != nil {
+		log.Println("failed to save daily history:", err)
+	}
+}
+
+func (dg *DailyGame) finish(status string) {
+	dg.playing = false
+	dg.record(status)
+
+	// Log the summary and keep a copy next to the history so it can be shared
+	log.Print("daily summary\n", dg.result.Summary)
+	path, err := ConfigPath(DAILY_SHARE_FILE)
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(path), 0o755)
+	}
+
+	if err == nil {
+		err = os.WriteFile(path, []byte(dg.result.Summary), 0o644)
+	}
+
+	if err != nil {
+		dg.message = "summary written to the log"
+		return
+	}
+
+	dg.message = "summary saved to " + path
+}
+
+// end
@@ -0,0 +1,134 @@
+package game
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDailyDate(t *testing.T) {
+	loc := time.FixedZone("UTC+3", 3*60*60)
+	now := time.Date(2026, 3, 2, 1, 30, 0, 0, loc)
+
+	if date := DailyDate(now); date != "2026-03-01" {
+		t.Errorf("expected the UTC date 2026-03-01, found %s", date)
+	}
+}
+
+func TestDailySeed(t *testing.T) {
+	if DailySeed("2026-03-01") != DailySeed("2026-03-01") {
+		t.Errorf("same date should give the same seed")
+	}
+
+	if DailySeed("2026-03-01") == DailySeed("2026-03-02") {
+		t.Errorf("different dates should give different seeds")
+	}
+}
+
+func TestDailySpawnSequence(t *testing.T) {
+	moves := []Direction{LEFT, UP, RIGHT, DOWN, LEFT, LEFT, UP, RIGHT}
+
+	play := func() [][]Cell {
+		g := &Game{board: Board{cells: emptyCells(4)}, spawner: NewDailySpawner("2026-03-01")}
+		PlaceSpawn(g)
+		for _, d := range moves {
+			PlayMove(g, d)
+			FinishAnimations(g)
+		}
+		return g.board.cells
+	}
+
+	first, second := play(), play()
+	for i := range first {
+		for j := range first[i] {
+			if first[i][j].val != second[i][j].val || first[i][j].isRendered != second[i][j].isRendered {
+				t.Fatalf("boards differ at row %d col %d", i, j)
+			}
+		}
+	}
+}
+
+func TestDailyHistory(t *testing.T) {
+	h := DailyHistory{}
+	h.Record(DailyResult{Date: "2026-03-01", Status: DAILY_PLAYING})
+	h.Record(DailyResult{Date: "2026-03-01", Status: DAILY_OVER, Score: 100})
+	h.Record(DailyResult{Date: "2026-03-02", Status: DAILY_PLAYING})
+
+	if len(h.Results) != 2 {
+		t.Fatalf("expected 2 results, found %d", len(h.Results))
+	}
+
+	r := h.Find("2026-03-01")
+	if r == nil || r.Status != DAILY_OVER || r.Score != 100 {
+		t.Errorf("result was not replaced, found %+v", r)
+	}
+
+	if h.Find("2026-03-03") != nil {
+		t.Errorf("unexpected result for a date that was not played")
+	}
+}
+
+func TestDailySummary(t *testing.T) {
+	result := DailyResult{Date: "2026-03-01", Score: 20, MaxTile: 16, Moves: 3}
+	summary := DailySummary(result, [][]Cell{{m(2), m(16)}, {m(0), m(1024)}})
+
+	expected := "2048 daily 2026-03-01\nScore 20, best tile 16, 3 moves\n🟨🟧\n⬛🟪\n"
+	if summary != expected {
+		t.Errorf("expected %q, found %q", expected, summary)
+	}
+}
+
+func emptyCells(size int) [][]Cell {
+	cells := make([][]Cell, size)
+	for i := range cells {
+		cells[i] = make([]Cell, size)
+		for j := range cells[i] {
+			cells[i][j] = Cell{pos_x: i, pos_y: j}
+		}
+	}
+
+	return cells
+}
+
+func TestDailyKeepsUnreadableHistory(t *testing.T) {
+	defer SetCellSize(CELL_SIZE)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	path, err := ConfigPath(DAILY_HISTORY_FILE)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name     string
+		existing string
+		expected string
+	}{
+		{name: "corrupt file", existing: `{"results": [{"date": "2026-01-01", "sco`, expected: `{"results": [{"date": "2026-01-01", "sco`},
+		{name: "readable file", existing: `{"results": []}`, expected: `"date": "` + DailyDate(time.Now()) + `"`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte(tc.existing), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			NewDailyGame(NewApp())
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(data), tc.expected) {
+				t.Errorf("expected the history to contain %s, found %s", tc.expected, data)
+			}
+		})
+	}
+}
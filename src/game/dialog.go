@@ -0,0 +1,59 @@
+package game
+
+import (
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Asks a yes or no question over the current screen
+// Yes closes the dialog and runs onYes, No and Escape only close it
+type ConfirmDialog struct {
+	app     *App
+	ui      *UI
+	message string
+	onYes   func()
+}
+
+func NewConfirmDialog(app *App, message string, onYes func()) *ConfirmDialog {
+	return &ConfirmDialog{app: app, ui: NewUI(), message: message, onYes: onYes}
+}
+
+func (cd *ConfirmDialog) IsOverlay() bool {
+	return true
+}
+
+func (cd *ConfirmDialog) Update() error {
+	w, h := ScreenSize()
+	screen := image.Rect(0, 0, w, h)
+	ui := cd.ui
+
+	ui.Begin(screen)
+	ui.BeginModal(screen, cd.message, 1)
+	ui.BeginRow(2)
+	yes := ui.Button("Yes")
+	// Saying no is the safe choice
+	ui.DefaultFocus()
+	no := ui.Button("No")
+	ui.EndRow()
+	ui.EndModal()
+	ui.End()
+
+	switch {
+	case yes:
+		cd.app.Pop()
+		cd.onYes()
+	case no || ui.Back():
+		cd.app.Pop()
+	}
+
+	return nil
+}
+
+func (cd *ConfirmDialog) Draw(screen *ebiten.Image) {
+	cd.ui.Draw(screen)
+}
+
+func (cd *ConfirmDialog) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
+	return ScaledSize(outsideWidth, outsideHeight)
+}
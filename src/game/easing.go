@@ -0,0 +1,50 @@
+package game
+
+import "math"
+
+// Maps the linear progress of an animation (0 to 1) to its eased progress
+// Every easing starts at 0 and ends at 1, back and elastic overshoot in between
+type Easing func(t float64) float64
+
+func Linear(t float64) float64 {
+	return t
+}
+
+func EaseOutQuad(t float64) float64 {
+	return 1 - (1-t)*(1-t)
+}
+
+func EaseOutCubic(t float64) float64 {
+	return 1 - math.Pow(1-t, 3)
+}
+
+func EaseInOutCubic(t float64) float64 {
+	if t < 0.5 {
+		return 4 * t * t * t
+	}
+
+	return 1 - math.Pow(-2*t+2, 3)/2
+}
+
+// Overshoots the target a little before settling
+func EaseOutBack(t float64) float64 {
+	const c1 = 1.70158
+	const c3 = c1 + 1
+
+	return 1 + c3*math.Pow(t-1, 3) + c1*math.Pow(t-1, 2)
+}
+
+// Springs around the target with a decaying wobble
+func EaseOutElastic(t float64) float64 {
+	const c4 = 2 * math.Pi / 3
+
+	if t <= 0 {
+		return 0
+	}
+
+	if t >= 1 {
+		return 1
+	}
+
+	return math.Pow(2, -10*t)*math.Sin((t*10-0.75)*c4) + 1
+}
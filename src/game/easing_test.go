@@ -0,0 +1,31 @@
+package game
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEasingEndpoints(t *testing.T) {
+	easings := map[string]Easing{
+		"linear":         Linear,
+		"ease out quad":  EaseOutQuad,
+		"ease out cubic": EaseOutCubic,
+		"ease in out":    EaseInOutCubic,
+		"ease out back":  EaseOutBack,
+		"elastic":        EaseOutElastic,
+	}
+
+	for name, easing := range easings {
+		t.Run(name, func(t *testing.T) {
+			if math.Abs(easing(0)) > 1e-9 || math.Abs(easing(1)-1) > 1e-9 {
+				t.Errorf("expected to go from 0 to 1, found %f to %f", easing(0), easing(1))
+			}
+		})
+	}
+}
+
+func TestEaseOutBackOvershoots(t *testing.T) {
+	if EaseOutBack(0.8) <= 1 {
+		t.Errorf("expected to overshoot before settling, found %f", EaseOutBack(0.8))
+	}
+}
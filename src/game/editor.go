@@ -0,0 +1,310 @@
+package game
+
+import (
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+var MIN_EDITOR_SIZE = 2
+var MAX_EDITOR_SIZE = 8
+var MAX_EDITOR_VALUE = 131072
+
+type EditorTool int32
+
+const (
+	TOOL_VALUE EditorTool = iota // left click raises the value, right click lowers it
+	TOOL_BLOCK                   // click toggles a blocked cell
+	TOOL_SPAWN                   // left click appends a spawn of 2, right click a spawn of 4
+)
+
+// Lets the player build a position by clicking cells, try it out and save it
+type Editor struct {
+	app     *App
+	tool    EditorTool
+	puzzle  Puzzle
+	game    *Game
+	message string
+}
+
+func NewEditor(app *App) *Editor {
+	e := Editor{app: app, tool: TOOL_VALUE}
+	e.puzzle = Puzzle{
+		goal:     PuzzleGoal{goalType: GOAL_TILE, value: 2048},
+		maxMoves: 100,
+	}
+	e.puzzle.start.board = make([][]int, CELL_COUNT)
+	for i := range e.puzzle.start.board {
+		e.puzzle.start.board[i] = make([]int, CELL_COUNT)
+	}
+
+	e.game = NewGame(e.puzzle.start.NewBoard(), nil)
+	e.game.layout.spec = LayoutSpec{boxes: 3, footerLines: 4}
+
+	return &e
+}
+
+func (e *Editor) Update() error {
+	if Bindings().JustPressed(ACTION_QUIT) {
+		e.app.Pop()
+		return nil
+	}
+
+	ctrl := ebiten.IsKeyPressed(ebiten.KeyControl)
+
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyTab):
+		e.tool = (e.tool + 1) % 3
+	case inpututil.IsKeyJustPressed(ebiten.KeyBracketLeft):
+		e.resize(len(e.puzzle.start.board) - 1)
+	case inpututil.IsKeyJustPressed(ebiten.KeyBracketRight):
+		e.resize(len(e.puzzle.start.board) + 1)
+	case inpututil.IsKeyJustPressed(ebiten.KeyBackspace):
+		spawns := e.puzzle.start.spawns
+		if len(spawns) > 0 {
+			e.puzzle.start.spawns = spawns[:len(spawns)-1]
+		}
+	case inpututil.IsKeyJustPressed(ebiten.KeyG):
+		if e.puzzle.goal.goalType == GOAL_TILE {
+			e.puzzle.goal.goalType = GOAL_SINGLE_TILE
+		} else {
+			e.puzzle.goal.goalType = GOAL_TILE
+		}
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowUp):
+		e.puzzle.goal.value = min(e.puzzle.goal.value*2, MAX_EDITOR_VALUE)
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowDown):
+		e.puzzle.goal.value = max(e.puzzle.goal.value/2, 4)
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowRight):
+		e.puzzle.maxMoves++
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowLeft):
+		e.puzzle.maxMoves = max(e.puzzle.maxMoves-1, 1)
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnter):
+		e.testPlay()
+	case ctrl && inpututil.IsKeyJustPressed(ebiten.KeyS):
+		e.save(true)
+	case ctrl && inpututil.IsKeyJustPressed(ebiten.KeyP):
+		e.save(false)
+	}
+
+	left := inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft)
+	right := inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight)
+	if left || right {
+		cx, cy := ebiten.CursorPosition()
+		if row, col, ok := CellAt(e.game.board, cx, cy); ok {
+			e.edit(row, col, left)
+		}
+	}
+
+	return nil
+}
+
+func (e *Editor) Draw(screen *ebiten.Image) {
+	g := e.game
+
+	drawBackground(g, screen)
+	drawBoard(g, screen)
+
+	tools := []string{"VALUE", "BLOCK", "SPAWN"}
+	x, y := InfoBoxPosition(g, 0)
+	drawInfoBox(g, screen, x, y, "TOOL", tools[e.tool])
+	x, y = InfoBoxPosition(g, 1)
+	drawInfoBox(g, screen, x, y, "GOAL", e.puzzle.GoalText())
+	x, y = InfoBoxPosition(g, 2)
+	drawInfoBox(g, screen, x, y, "MOVES", strconv.Itoa(e.puzzle.maxMoves))
+
+	// Spawn order markers in the top left corner of each cell
+	markers := map[[2]int][]string{}
+	for i, s := range e.puzzle.start.spawns {
+		key := [2]int{s.pos_x, s.pos_y}
+		markers[key] = append(markers[key], fmt.Sprintf("%d:%d", i+1, s.val))
+	}
+
+	for key, labels := range markers {
+		c := g.board.cells[key[0]][key[1]]
+		txtOp := &text.DrawOptions{}
+		txtOp.ColorScale.ScaleWithColor(CurrentTheme().text)
+		txtOp.GeoM.Translate(float64(c.x+GAP/2), float64(c.y+GAP/2))
+		text.Draw(screen, strings.Join(labels, " "), Fonts().Face(FONT_CAPTION), txtOp)
+	}
+
+	help := []string{
+		"Tab: tool   [ ]: board size   Backspace: remove last spawn",
+		"G: goal type   Up/Down: goal tile   Left/Right: move limit",
+		"Enter: test play   Ctrl+S: save puzzle   Ctrl+P: save position",
+		e.message,
+	}
+
+	lh := int(Fonts().Face(FONT_CAPTION).Size * 1.5)
+	cx, top := FooterPosition(g)
+	for i, line := range help {
+		txtOp := &text.DrawOptions{}
+		txtOp.ColorScale.ScaleWithColor(CurrentTheme().mutedText)
+		DrawCenteredText(screen, Fonts().Face(FONT_CAPTION), line, cx, top+lh*(i+1), txtOp)
+	}
+}
+
+func (e *Editor) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
+	return ScaledSize(outsideWidth, outsideHeight)
+}
+
+func (e *Editor) Resize(width int, height int) {
+	ApplyLayout(e.game, image.Rect(0, 0, width, height))
+}
+
+func (e *Editor) edit(row int, col int, primary bool) {
+	start := &e.puzzle.start
+	val := start.board[row][col]
+
+	switch e.tool {
+	case TOOL_VALUE:
+		if val == BLOCKED_VALUE {
+			val = 0
+		}
+
+		if primary {
+			start.board[row][col] = NextTileValue(val, MAX_EDITOR_VALUE)
+		} else {
+			start.board[row][col] = PrevTileValue(val, MAX_EDITOR_VALUE)
+		}
+	case TOOL_BLOCK:
+		if val == BLOCKED_VALUE {
+			start.board[row][col] = 0
+			break
+		}
+
+		// A spawn on a blocked cell could never happen, newPosition rejects it
+		if slices.ContainsFunc(start.spawns, func(s Spawn) bool { return s.pos_x == row && s.pos_y == col }) {
+			e.message = "can not block a cell with spawns, remove them first"
+			return
+		}
+		start.board[row][col] = BLOCKED_VALUE
+	case TOOL_SPAWN:
+		if val == BLOCKED_VALUE {
+			e.message = "can not spawn on a blocked cell"
+			return
+		}
+
+		s := Spawn{pos_x: row, pos_y: col, val: 2}
+		if !primary {
+			s.val = 4
+		}
+		start.spawns = append(start.spawns, s)
+	}
+
+	e.game.board = start.NewBoard()
+	Relayout(e.game)
+}
+
+// Changes the board size, keeping the values and spawns that still fit
+func (e *Editor) resize(size int) {
+	if size < MIN_EDITOR_SIZE || size > MAX_EDITOR_SIZE {
+		return
+	}
+
+	start := &e.puzzle.start
+	board := make([][]int, size)
+	for i := range board {
+		board[i] = make([]int, size)
+		for j := range board[i] {
+			if i < len(start.board) && j < len(start.board) {
+				board[i][j] = start.board[i][j]
+			}
+		}
+	}
+
+	spawns := make([]Spawn, 0, len(start.spawns))
+	for _, s := range start.spawns {
+		if s.pos_x < size && s.pos_y < size {
+			spawns = append(spawns, s)
+		}
+	}
+
+	start.board = board
+	start.spawns = spawns
+	e.game.board = start.NewBoard()
+	Relayout(e.game)
+}
+
+// Plays the position as a puzzle, Escape or Enter on the result comes back to the editor
+func (e *Editor) testPlay() {
+	data, err := e.puzzle.Marshal()
+	if err == nil {
+		var p *Puzzle
+		p, err = ParsePuzzle(data)
+		if err == nil {
+			e.message = ""
+			e.app.SetScreen(NewPuzzleGame(e.app, e, p))
+			return
+		}
+	}
+
+	e.message = err.Error()
+}
+
+// Saves the position either as a puzzle or as a plain starting position
+// Puzzles go where the level select loads them from, see AssetDir
+func (e *Editor) save(asPuzzle bool) {
+	stamp := time.Now().Format("20060102-150405")
+	dir := AssetDir(POSITION_DIR)
+	var data []byte
+	var err error
+
+	if asPuzzle {
+		dir = AssetDir(PUZZLE_DIR)
+		e.puzzle.name = "Custom " + stamp
+		data, err = e.puzzle.Marshal()
+	} else {
+		data, err = e.puzzle.start.Marshal()
+	}
+
+	if err == nil {
+		err = os.MkdirAll(dir, 0o755)
+	}
+
+	path := filepath.Join(dir, "custom-"+stamp+".json")
+	if err == nil {
+		err = os.WriteFile(path, data, 0o644)
+	}
+
+	if err != nil {
+		e.message = "save failed: " + err.Error()
+		return
+	}
+
+	e.message = "saved " + path
+}
+
+// Next value when cycling a cell upwards: empty, 2, 4, ... max, empty
+func NextTileValue(val int, maxVal int) int {
+	if val == 0 {
+		return 2
+	}
+
+	if val >= maxVal {
+		return 0
+	}
+
+	return val * 2
+}
+
+// Reverse of NextTileValue
+func PrevTileValue(val int, maxVal int) int {
+	if val == 0 {
+		return maxVal
+	}
+
+	if val <= 2 {
+		return 0
+	}
+
+	return val / 2
+}
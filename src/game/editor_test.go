@@ -0,0 +1,71 @@
+package game
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEditorBlockCell(t *testing.T) {
+	defer SetCellSize(CELL_SIZE)
+	e := NewEditor(NewApp())
+
+	testCases := []struct {
+		name     string
+		tool     EditorTool
+		row, col int
+		expected int
+	}{
+		{name: "spawn", tool: TOOL_SPAWN, row: 1, col: 1, expected: 0},
+		{name: "block the spawn cell", tool: TOOL_BLOCK, row: 1, col: 1, expected: 0},
+		{name: "block another cell", tool: TOOL_BLOCK, row: 2, col: 2, expected: BLOCKED_VALUE},
+		{name: "unblock it", tool: TOOL_BLOCK, row: 2, col: 2, expected: 0},
+	}
+
+	for _, tc := range testCases {
+		e.tool = tc.tool
+		e.edit(tc.row, tc.col, true)
+
+		if actual := e.puzzle.start.board[tc.row][tc.col]; actual != tc.expected {
+			t.Errorf("%s: expected %d, found %d", tc.name, tc.expected, actual)
+		}
+	}
+
+	if len(e.puzzle.start.spawns) != 1 {
+		t.Errorf("expected the spawn to be kept, found %v", e.puzzle.start.spawns)
+	}
+
+	if _, err := newPosition(e.puzzle.start.board, e.puzzle.start.spawnEntries()); err != nil {
+		t.Errorf("expected a valid position, found %v", err)
+	}
+}
+
+func TestAssetDir(t *testing.T) {
+	dir := t.TempDir()
+	// Browsers have no binary on disk
+	exe, exeErr := os.Executable()
+
+	testCases := []struct {
+		name     string
+		dir      string
+		expected string
+		skip     bool
+	}{
+		{name: "absolute", dir: dir, expected: dir},
+		{name: "in the working directory", dir: ".", expected: "."},
+		{name: "missing", dir: "no/such/dir", expected: "no/such/dir"},
+		{name: "next to the binary", dir: filepath.Base(exe), expected: exe, skip: exeErr != nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skip(exeErr)
+			}
+
+			if actual := AssetDir(tc.dir); actual != tc.expected {
+				t.Errorf("expected %s, found %s", tc.expected, actual)
+			}
+		})
+	}
+}
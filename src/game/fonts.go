@@ -0,0 +1,145 @@
+package game
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"runtime"
+
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+type FontTier int32
+
+const (
+	FONT_TITLE FontTier = iota
+	FONT_BODY
+	FONT_TILE
+	FONT_CAPTION
+)
+
+const DEFAULT_FONT = "assets/Roboto-Thin.ttf"
+
+// Hands out faces of the current theme font in a few fixed size tiers
+// Every tier follows the cell size, which the layout fits to the window size and device scale
+type FontManager struct {
+	source    *text.GoTextFaceSource
+	cellSize  int
+	faces     map[FontTier]*text.GoTextFace
+	tileFaces map[tileFaceKey]*text.GoTextFace
+}
+
+// Tile labels are digits of the same width, so labels of the same length share a face
+type tileFaceKey struct {
+	length int
+	size   int
+}
+
+var fonts = NewFontManager()
+
+func NewFontManager() *FontManager {
+	return &FontManager{faces: map[FontTier]*text.GoTextFace{}, tileFaces: map[tileFaceKey]*text.GoTextFace{}}
+}
+
+func Fonts() *FontManager {
+	return fonts
+}
+
+// Size of a tier for the current cell size
+func TierSize(tier FontTier) float64 {
+	switch tier {
+	case FONT_TITLE:
+		return float64(CELL_SIZE) / 3
+	case FONT_TILE:
+		return float64(CELL_SIZE) / 3
+	case FONT_CAPTION:
+		return float64(CELL_SIZE) / 8
+	default:
+		return float64(CELL_SIZE) / 4
+	}
+}
+
+// Reads the font of the current theme
+// WASM has no files to read, it and unreadable font files use the built in Go font
+func loadFontSource() *text.GoTextFaceSource {
+	data := goregular.TTF
+
+	if runtime.GOOS != "js" || runtime.GOARCH != "wasm" {
+		path := DEFAULT_FONT
+		if CurrentTheme().font != "" {
+			path = CurrentTheme().font
+		}
+
+		if fontData, err := os.ReadFile(path); err == nil {
+			data = fontData
+		} else {
+			log.Println("failed to read font, using the default:", err)
+		}
+	}
+
+	source, err := text.NewGoTextFaceSource(bytes.NewReader(data))
+	if err != nil {
+		log.Fatal("failed to load font:", err)
+	}
+
+	return source
+}
+
+// impl FontManager
+
+func (fm *FontManager) Face(tier FontTier) *text.GoTextFace {
+	// The layout changed the cell size since the faces were made
+	if fm.cellSize != CELL_SIZE {
+		fm.cellSize = CELL_SIZE
+		clear(fm.faces)
+		clear(fm.tileFaces)
+	}
+
+	if face, ok := fm.faces[tier]; ok {
+		return face
+	}
+
+	if fm.source == nil {
+		fm.source = loadFontSource()
+	}
+
+	face := &text.GoTextFace{Source: fm.source, Size: TierSize(tier)}
+	fm.faces[tier] = face
+	return face
+}
+
+// Face for a tile label on a tile of the given size
+// Long values like 131072 shrink until they fit inside the tile with a GAP on both sides
+func (fm *FontManager) TileFace(label string, size int) *text.GoTextFace {
+	base := fm.Face(FONT_TILE)
+	key := tileFaceKey{length: len(label), size: size}
+	if face, ok := fm.tileFaces[key]; ok {
+		return face
+	}
+
+	face := &text.GoTextFace{Source: base.Source, Size: base.Size * float64(size) / float64(CELL_SIZE)}
+
+	// Widths do not scale exactly with the size at small sizes, so shrink a few times
+	maxWidth := float64(size - 2*GAP)
+	for range 4 {
+		w, _ := text.Measure(label, face, 0)
+		if w <= maxWidth || w == 0 {
+			break
+		}
+
+		face.Size *= maxWidth / w * 0.98
+	}
+
+	fm.tileFaces[key] = face
+	return face
+}
+
+// Reads the font again, e.g. after switching to a theme with another font
+func (fm *FontManager) Reload() {
+	fm.source = nil
+	clear(fm.faces)
+	clear(fm.tileFaces)
+}
+
+// end
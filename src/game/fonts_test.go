@@ -0,0 +1,59 @@
+package game
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+func TestTileLabelsFit(t *testing.T) {
+	fm := NewFontManager()
+
+	for val := 2; val <= 131072; val *= 2 {
+		label := strconv.Itoa(val)
+		for _, size := range []int{CELL_SIZE, CELL_SIZE / 2} {
+			face := fm.TileFace(label, size)
+			if w, _ := text.Measure(label, face, 0); w > float64(size-2*GAP)+0.5 {
+				t.Errorf("%s does not fit a %d pixel tile, %f wide", label, size, w)
+			}
+		}
+	}
+
+	if short, long := fm.TileFace("2", CELL_SIZE), fm.TileFace("131072", CELL_SIZE); long.Size >= short.Size {
+		t.Errorf("expected long labels to shrink, found %f for 2 and %f for 131072", short.Size, long.Size)
+	}
+}
+
+func TestTileFaceCache(t *testing.T) {
+	fm := NewFontManager()
+
+	if fm.TileFace("16", CELL_SIZE) != fm.TileFace("64", CELL_SIZE) {
+		t.Errorf("expected labels of the same length to share a face")
+	}
+
+	if fm.TileFace("16", CELL_SIZE) == fm.TileFace("16", CELL_SIZE/2) {
+		t.Errorf("expected another face for another tile size")
+	}
+
+	face := fm.TileFace("16", CELL_SIZE)
+	fm.Reload()
+	if fm.TileFace("16", CELL_SIZE) == face {
+		t.Errorf("expected a reload to drop the cached faces")
+	}
+}
+
+func TestFontTiers(t *testing.T) {
+	fm := NewFontManager()
+	if fm.Face(FONT_TITLE).Size <= fm.Face(FONT_BODY).Size || fm.Face(FONT_BODY).Size <= fm.Face(FONT_CAPTION).Size {
+		t.Errorf("expected title > body > caption")
+	}
+
+	body, cellSize := fm.Face(FONT_BODY).Size, CELL_SIZE
+	defer SetCellSize(cellSize)
+
+	SetCellSize(cellSize * 2)
+	if actual := fm.Face(FONT_BODY).Size; actual != body*2 {
+		t.Errorf("expected the body to follow the cell size to %f, found %f", body*2, actual)
+	}
+}
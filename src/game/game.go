@@ -1,22 +1,30 @@
 package game
 
 import (
-	"bytes"
 	"fmt"
-	"log"
-	"os"
-	"runtime"
+	"math/bits"
+	"time"
 
-	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/text/v2"
-	"golang.org/x/image/font/gofont/goregular"
+	"mkoca/2048/src/sound"
 )
 
 var CELL_SIZE = 120
 var GAP = 10
 var CELL_COUNT = 4
 var TARGET_TPS = 60
-var CREATE_CELL_ANIMATION_DURATION = TARGET_TPS / 8
+var CREATE_CELL_ANIMATION_DURATION = time.Second / 8
+var MOVE_CELL_ANIMATION_DURATION = time.Second / 10
+var POP_ANIMATION_DURATION = time.Second / 10
+var SCORE_ANIMATION_DURATION = time.Second / 2
+var MAX_ANIMATION_STEP = time.Second / 10
+var POP_SCALE = 0.15
+var WIN_TILE = 2048
+
+// Animations play this many times as fast, see the animation_speed setting
+var ANIMATION_SPEED = 1.0
+
+// Animations jump straight to their end
+var REDUCED_MOTION = false
 
 type GameStatus int32
 
@@ -24,6 +32,8 @@ const (
 	RUNNING GameStatus = iota
 	FINISHED
 	GAME_OVER
+	// Nothing moves, see PauseGame
+	PAUSED
 )
 
 // TODO
@@ -33,14 +43,20 @@ type Vec2 struct {
 	y int
 }
 
+// origin is the screen position the tile started the last move from
+// When the last move merged two tiles into this one, mergeOrigin is where the other tile started
 type Cell struct {
-	x          int
-	y          int
-	pos_x      int
-	pos_y      int
-	val        int
-	isRendered bool
-	animation  Animation
+	x           int
+	y           int
+	pos_x       int
+	pos_y       int
+	val         int
+	isRendered  bool
+	isBlocked   bool
+	animation   Animation
+	origin      Vec2
+	mergeOrigin Vec2
+	merged      bool
 }
 
 type Background struct {
@@ -56,11 +72,29 @@ type Board struct {
 }
 
 type Game struct {
-	board      Board
-	score      int
-	fontSource *text.GoTextFaceSource
-	fontFace   *text.GoTextFace
-	status     GameStatus
+	board   Board
+	score   int
+	moves   int
+	status  GameStatus
+	spawner Spawner
+	target  int
+	// Own keys of the game, e.g. one player in the versus mode, nil follows the current bindings
+	keys     KeyBindings
+	swipes   *SwipeDetector
+	gamepad  Gamepad
+	effects  []Animation
+	scorePos Vec2
+	lastTick time.Time
+	layout   ScreenLayout
+	history  []Snapshot
+	redo     []Snapshot
+	// Every board before a move since the game started, undone moves wait in undone until they are redone
+	recording []Snapshot
+	undone    []Snapshot
+	hint      Direction
+	showHint  bool
+	unpaused  GameStatus
+	input     InputQueue
 }
 
 func FormatCell(cell Cell) string {
@@ -68,28 +102,48 @@ func FormatCell(cell Cell) string {
 }
 
 func InitGame() *Game {
-	screen_x, screen_y := ebiten.WindowSize()
-	grid_x, grid_y := CELL_COUNT*CELL_SIZE+(CELL_COUNT+1)*GAP, CELL_COUNT*CELL_SIZE+(CELL_COUNT+1)*GAP
+	g := NewGame(NewBoard(CELL_COUNT), &RandomSpawner{fourChance: FOUR_CHANCE})
+	g.layout.spec.footerLines = 1
+	PlaceSpawn(g)
+
+	return g
+}
+
+// Creates a game around an already populated board
+// Unlike InitGame no initial tile is spawned
+func NewGame(b Board, spawner Spawner) *Game {
+	g := Game{board: b, status: RUNNING, spawner: spawner, target: WIN_TILE, swipes: NewSwipeDetector()}
+	g.layout.spec = LayoutSpec{boxes: 1}
+
+	return &g
+}
+
+// Creates an empty square board with the given number of cells per side, centered on the window
+func NewBoard(size int) Board {
+	screen_x, screen_y := ScreenSize()
+	grid_x, grid_y := BoardSize(size), BoardSize(size)
 	bg_x_offset, bg_y_offset := (screen_x-grid_x)/2, (screen_y-grid_y)/2
 
-	// TODO
-	// This section could be better
-	// For now good enough for basic wasm support
-	if runtime.GOOS == "js" && runtime.GOARCH == "wasm" {
-		bg_x_offset = 0
-		bg_y_offset = 0
-	}
+	return NewBoardAt(size, bg_x_offset, bg_y_offset)
+}
+
+// Width and height in pixels of a board with the given number of cells per side
+func BoardSize(size int) int {
+	return size*CELL_SIZE + (size+1)*GAP
+}
 
+// Creates an empty square board with its top left corner at x, y
+func NewBoardAt(size int, x int, y int) Board {
 	background := Background{
-		x:  bg_x_offset,
-		y:  bg_y_offset,
-		dx: grid_x,
-		dy: grid_y,
+		x:  x,
+		y:  y,
+		dx: BoardSize(size),
+		dy: BoardSize(size),
 	}
 
-	cells := make([][]Cell, CELL_COUNT)
+	cells := make([][]Cell, size)
 	for i := range cells {
-		cells[i] = make([]Cell, CELL_COUNT)
+		cells[i] = make([]Cell, size)
 
 		for j := range cells[i] {
 			x, y := CalculateActualCellPosition(background.x, background.y, j, i, CELL_SIZE, GAP)
@@ -97,58 +151,14 @@ func InitGame() *Game {
 		}
 	}
 
-	b := Board{bg: background, cells: cells}
-	g := Game{board: b, status: RUNNING}
-
-	emptyCell, err := GetRandomCell(g.board.cells)
-
-	if err == nil {
-		selectedCell := &g.board.cells[emptyCell.pos_x][emptyCell.pos_y]
-		selectedCell.isRendered = true
-		selectedCell.val = 2
-	}
-
-	// TODO
-	// Current font fetching is a bit of a mess
-	// We need different types of fonts for different purposes and also scale it properly based on screen size
-	// Also load a better font for WASM
-	if runtime.GOARCH == "wasm" && runtime.GOOS == "js" {
-		fontData, err := text.NewGoTextFaceSource(bytes.NewReader(goregular.TTF))
-		if err != nil {
-			log.Fatal("failed to load default WASM font:", err)
-		}
-
-		g.fontSource = fontData
-		g.fontFace = &text.GoTextFace{
-			Source: g.fontSource,
-			Size:   float64(CELL_SIZE) / 4 * ebiten.Monitor().DeviceScaleFactor(),
-		}
-	} else {
-		fontData, err := os.ReadFile("assets/Roboto-Thin.ttf")
-		if err != nil {
-			panic("font read fail")
-		}
-
-		g.fontSource, err = text.NewGoTextFaceSource(bytes.NewReader(fontData))
-
-		if err != nil {
-			panic("font fail")
-		}
-
-		g.fontFace = &text.GoTextFace{
-			Source: g.fontSource,
-			Size:   float64(CELL_SIZE) / 4 * ebiten.Monitor().DeviceScaleFactor(),
-		}
-	}
-
-	return &g
+	return Board{bg: background, cells: cells}
 }
 
-func IsGameFinished(cells [][]Cell) bool {
+func IsGameFinished(cells [][]Cell, target int) bool {
 	for i := 0; i < len(cells); i++ {
 		for j := 0; j < len(cells[i]); j++ {
 			c := cells[i][j]
-			if c.isRendered && c.val == 2048 {
+			if c.isRendered && c.val >= target {
 				return true
 			}
 		}
@@ -167,18 +177,52 @@ func IsGameOver(cells [][]Cell) bool {
 	}
 }
 
+// Keys of the game, read on every use so changed bindings apply to running games too
+func GameKeys(g *Game) KeyBindings {
+	if g.keys != nil {
+		return g.keys
+	}
+
+	return Bindings()
+}
+
 func ResetGame(g *Game) {
 	ResetBoard(&g.board)
 	g.score = 0
+	g.moves = 0
 	g.status = RUNNING
+	g.history = nil
+	g.redo = nil
+	g.recording = nil
+	g.undone = nil
+	g.showHint = false
+	g.input.Clear()
+	for _, effect := range g.effects {
+		effect.Cancel()
+	}
+	g.effects = nil
 
-	emptyCell, err := GetRandomCell(g.board.cells)
+	PlaceSpawn(g)
+}
 
-	if err == nil {
-		selectedCell := &g.board.cells[emptyCell.pos_x][emptyCell.pos_y]
-		selectedCell.isRendered = true
-		selectedCell.val = 2
+// Freezes the game including its animations until ResumeGame
+func PauseGame(g *Game) {
+	if g.status == PAUSED {
+		return
 	}
+
+	g.unpaused = g.status
+	g.status = PAUSED
+}
+
+func ResumeGame(g *Game) {
+	if g.status != PAUSED {
+		return
+	}
+
+	g.status = g.unpaused
+	// The time spent paused must not count towards the animations
+	g.lastTick = time.Time{}
 }
 
 func ResetBoard(b *Board) {
@@ -187,6 +231,12 @@ func ResetBoard(b *Board) {
 			c := &b.cells[i][j]
 			c.isRendered = false
 			c.val = 0
+
+			// Cancelling keeps pending callbacks from touching the fresh board
+			if c.animation != nil {
+				c.animation.Cancel()
+				c.animation = nil
+			}
 		}
 	}
 }
@@ -194,7 +244,7 @@ func ResetBoard(b *Board) {
 func HasRunningAnimation(g *Game) bool {
 	for _, row := range g.board.cells {
 		for _, cell := range row {
-			if cell.animation != nil && cell.animation.GetStatus() != ANIM_FINISHED {
+			if cell.animation != nil && cell.animation.GetStatus() != ANIM_FINISHED && cell.animation.GetStatus() != ANIM_CANCELLED {
 				return true
 			}
 		}
@@ -202,3 +252,67 @@ func HasRunningAnimation(g *Game) bool {
 
 	return false
 }
+
+// Moves cells for a given direction and spawns a new cell if the board changed
+// Returns the number of movements and the merge score, same as Move
+func PlayMove(g *Game, d Direction) (int, int) {
+	before := TakeSnapshot(g)
+	totalNumOfMovements, totalMergeScore := Move(g, d)
+
+	// A merge without any shift still changes the board
+	if totalNumOfMovements > 0 || totalMergeScore > 0 {
+		PushHistory(g, before)
+		before.move = d
+		g.recording = append(g.recording, before)
+		StartMoveAnimations(g)
+		SpawnCellAfter(g, MOVE_CELL_ANIMATION_DURATION)
+		g.score += totalMergeScore
+
+		if totalMergeScore > 0 {
+			g.effects = append(g.effects, ScoreGainAnimation(totalMergeScore, SCORE_ANIMATION_DURATION))
+		}
+		g.moves++
+		playMoveSound(g)
+	}
+
+	return totalNumOfMovements, totalMergeScore
+}
+
+// Merges sound higher the larger the largest merged tile, moves without merges just slide
+func playMoveSound(g *Game) {
+	merged := 0
+	for _, row := range g.board.cells {
+		for _, c := range row {
+			if c.merged {
+				merged = max(merged, c.val)
+			}
+		}
+	}
+
+	if merged > 0 {
+		sound.Play(sound.MERGE, bits.Len(uint(merged))-1)
+	} else {
+		sound.Play(sound.SLIDE, 0)
+	}
+}
+
+// Slides every tile that moved or merged during the last Move from its origin to its cell
+// Merged tiles pop once they arrive
+func StartMoveAnimations(g *Game) {
+	for i, row := range g.board.cells {
+		for j := range row {
+			c := &g.board.cells[i][j]
+			pos := Vec2{x: c.x, y: c.y}
+
+			if !c.isRendered || (c.origin == pos && !c.merged) {
+				continue
+			}
+
+			if c.merged {
+				c.animation = Sequence(MoveCellAnimation(*c, MOVE_CELL_ANIMATION_DURATION), PopCellAnimation(*c, POP_ANIMATION_DURATION))
+			} else {
+				c.animation = MoveCellAnimation(*c, MOVE_CELL_ANIMATION_DURATION)
+			}
+		}
+	}
+}
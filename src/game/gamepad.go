@@ -0,0 +1,191 @@
+package game
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+type GamepadAction int32
+
+const (
+	PAD_UNDO GamepadAction = iota
+	PAD_NEW_GAME
+	PAD_MENU
+	// Hands the gamepad over to the next player in multi-board modes
+	PAD_SWITCH_PLAYER
+	// Picks and leaves menu entries
+	PAD_CONFIRM
+	PAD_BACK
+)
+
+// Buttons of the standard layout for every action, X undoes, Y starts a new game and Start opens the menu
+// A picks the focused menu entry and B goes back, every action has a button of its own
+var GAMEPAD_BUTTONS = map[GamepadAction]ebiten.StandardGamepadButton{
+	PAD_UNDO:          ebiten.StandardGamepadButtonRightLeft,
+	PAD_NEW_GAME:      ebiten.StandardGamepadButtonRightTop,
+	PAD_MENU:          ebiten.StandardGamepadButtonCenterRight,
+	PAD_SWITCH_PLAYER: ebiten.StandardGamepadButtonCenterLeft,
+	PAD_CONFIRM:       ebiten.StandardGamepadButtonRightBottom,
+	PAD_BACK:          ebiten.StandardGamepadButtonRightRight,
+}
+
+// Stick deflections below this are ignored, between 0 and 1
+var GAMEPAD_DEAD_ZONE = 0.5
+
+// A held direction moves once, again after the delay and then every interval
+var GAMEPAD_REPEAT_DELAY = time.Second * 3 / 10
+var GAMEPAD_REPEAT_INTERVAL = time.Second * 3 / 20
+
+// Turns a held direction into single moves with key repeat
+type directionRepeat struct {
+	held Direction
+	down bool
+	next time.Time
+}
+
+// A gamepad controlling one game
+type Gamepad struct {
+	id        ebiten.GamepadID
+	connected bool
+	repeat    directionRepeat
+}
+
+// Direction of a stick deflected by x, y, screen coordinates with y growing downwards
+// Returns false inside the dead zone
+func StickDirection(x float64, y float64, deadZone float64) (Direction, bool) {
+	if math.Hypot(x, y) < deadZone {
+		return UP, false
+	}
+
+	if math.Abs(x) > math.Abs(y) {
+		if x > 0 {
+			return RIGHT, true
+		}
+		return LEFT, true
+	}
+
+	if y > 0 {
+		return DOWN, true
+	}
+	return UP, true
+}
+
+// Gives every game a connected gamepad, in the order they were connected
+// Games keep their gamepad while it stays connected, PAD_SWITCH_PLAYER swaps it with the next game
+func AssignGamepads(games []*Game) {
+	ids := ebiten.AppendGamepadIDs(nil)
+	slices.Sort(ids)
+
+	taken := map[ebiten.GamepadID]bool{}
+	for _, g := range games {
+		if g.gamepad.connected && slices.Contains(ids, g.gamepad.id) {
+			taken[g.gamepad.id] = true
+		} else {
+			g.gamepad = Gamepad{}
+		}
+	}
+
+	for _, g := range games {
+		if g.gamepad.connected {
+			continue
+		}
+
+		for _, id := range ids {
+			if !taken[id] {
+				g.gamepad = Gamepad{id: id, connected: true}
+				taken[id] = true
+				break
+			}
+		}
+	}
+
+	for i, g := range games {
+		if len(games) > 1 && g.gamepad.JustPressed(PAD_SWITCH_PLAYER) {
+			next := games[(i+1)%len(games)]
+			g.gamepad, next.gamepad = next.gamepad, g.gamepad
+			break
+		}
+	}
+}
+
+// impl directionRepeat
+
+// Feeds the currently held direction, returns a direction whenever it should move the board
+func (r *directionRepeat) Update(dir Direction, down bool, now time.Time) (Direction, bool) {
+	if !down {
+		r.down = false
+		return dir, false
+	}
+
+	if !r.down || r.held != dir {
+		r.held, r.down = dir, true
+		r.next = now.Add(GAMEPAD_REPEAT_DELAY)
+		return dir, true
+	}
+
+	if now.Before(r.next) {
+		return dir, false
+	}
+
+	r.next = now.Add(GAMEPAD_REPEAT_INTERVAL)
+	return dir, true
+}
+
+// end
+
+// impl Gamepad
+
+// Direction held on the D-pad or the left stick, with repeat while it stays held
+func (gp *Gamepad) Direction(now time.Time) (Direction, bool) {
+	if !gp.connected {
+		return UP, false
+	}
+
+	dir, down := gp.heldDirection()
+	return gp.repeat.Update(dir, down, now)
+}
+
+func (gp *Gamepad) heldDirection() (Direction, bool) {
+	if !ebiten.IsStandardGamepadLayoutAvailable(gp.id) {
+		// Other gamepads usually report their main stick on the first two axes
+		if ebiten.GamepadAxisCount(gp.id) < 2 {
+			return UP, false
+		}
+		return StickDirection(ebiten.GamepadAxisValue(gp.id, 0), ebiten.GamepadAxisValue(gp.id, 1), GAMEPAD_DEAD_ZONE)
+	}
+
+	pad := []struct {
+		button ebiten.StandardGamepadButton
+		dir    Direction
+	}{
+		{ebiten.StandardGamepadButtonLeftTop, UP},
+		{ebiten.StandardGamepadButtonLeftRight, RIGHT},
+		{ebiten.StandardGamepadButtonLeftBottom, DOWN},
+		{ebiten.StandardGamepadButtonLeftLeft, LEFT},
+	}
+
+	for _, p := range pad {
+		if ebiten.IsStandardGamepadButtonPressed(gp.id, p.button) {
+			return p.dir, true
+		}
+	}
+
+	x := ebiten.StandardGamepadAxisValue(gp.id, ebiten.StandardGamepadAxisLeftStickHorizontal)
+	y := ebiten.StandardGamepadAxisValue(gp.id, ebiten.StandardGamepadAxisLeftStickVertical)
+	return StickDirection(x, y, GAMEPAD_DEAD_ZONE)
+}
+
+// True on the tick the button of action goes down
+func (gp *Gamepad) JustPressed(action GamepadAction) bool {
+	if !gp.connected || !ebiten.IsStandardGamepadLayoutAvailable(gp.id) {
+		return false
+	}
+
+	return inpututil.IsStandardGamepadButtonJustPressed(gp.id, GAMEPAD_BUTTONS[action])
+}
+
+// end
@@ -0,0 +1,75 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+func TestStickDirection(t *testing.T) {
+	testCases := []struct {
+		name     string
+		x, y     float64
+		expected Direction
+		ok       bool
+	}{
+		{name: "rest", x: 0.1, y: -0.2, ok: false},
+		{name: "right", x: 0.9, y: 0.2, expected: RIGHT, ok: true},
+		{name: "left", x: -0.7, y: 0, expected: LEFT, ok: true},
+		{name: "up", x: 0.3, y: -0.8, expected: UP, ok: true},
+		{name: "down", x: 0, y: 1, expected: DOWN, ok: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			d, ok := StickDirection(tc.x, tc.y, 0.5)
+			if ok != tc.ok {
+				t.Fatalf("expected ok to be %t, found %t", tc.ok, ok)
+			}
+
+			if ok && d != tc.expected {
+				t.Errorf("expected %d, found %d", tc.expected, d)
+			}
+		})
+	}
+}
+
+func TestDirectionRepeat(t *testing.T) {
+	start := time.Now()
+	at := func(d time.Duration) time.Time { return start.Add(d) }
+
+	steps := []struct {
+		name     string
+		dir      Direction
+		down     bool
+		at       time.Time
+		expected bool
+	}{
+		{name: "press moves", dir: LEFT, down: true, at: at(0), expected: true},
+		{name: "held before delay", dir: LEFT, down: true, at: at(GAMEPAD_REPEAT_DELAY / 2), expected: false},
+		{name: "held after delay", dir: LEFT, down: true, at: at(GAMEPAD_REPEAT_DELAY), expected: true},
+		{name: "held before interval", dir: LEFT, down: true, at: at(GAMEPAD_REPEAT_DELAY + GAMEPAD_REPEAT_INTERVAL/2), expected: false},
+		{name: "held after interval", dir: LEFT, down: true, at: at(GAMEPAD_REPEAT_DELAY + GAMEPAD_REPEAT_INTERVAL), expected: true},
+		{name: "other direction moves at once", dir: UP, down: true, at: at(GAMEPAD_REPEAT_DELAY + GAMEPAD_REPEAT_INTERVAL), expected: true},
+		{name: "release", dir: UP, down: false, at: at(time.Second), expected: false},
+		{name: "press again", dir: UP, down: true, at: at(time.Second), expected: true},
+	}
+
+	var r directionRepeat
+	for _, s := range steps {
+		if _, ok := r.Update(s.dir, s.down, s.at); ok != s.expected {
+			t.Errorf("%s: expected %t, found %t", s.name, s.expected, ok)
+		}
+	}
+}
+
+func TestGamepadButtonsAreDistinct(t *testing.T) {
+	actions := map[ebiten.StandardGamepadButton]GamepadAction{}
+	for action, button := range GAMEPAD_BUTTONS {
+		if other, ok := actions[button]; ok {
+			t.Errorf("expected every action to have its own button, found %d and %d on %d", other, action, button)
+		}
+		actions[button] = action
+	}
+}
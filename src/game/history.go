@@ -0,0 +1,110 @@
+package game
+
+// Number of moves that can be taken back, the oldest ones are forgotten first
+var MAX_UNDO = 64
+
+// Board, score and move count of a game before one of its moves
+// In recordings move is the direction played on the board
+type Snapshot struct {
+	cells [][]Cell
+	score int
+	moves int
+	move  Direction
+}
+
+func TakeSnapshot(g *Game) Snapshot {
+	return Snapshot{cells: CopyCells(g.board.cells), score: g.score, moves: g.moves}
+}
+
+// Remembers s as the state to go back to with the next Undo
+// A new move forgets every move that was taken back
+func PushHistory(g *Game, s Snapshot) {
+	g.redo = nil
+	g.undone = nil
+	g.history = pushSnapshot(g.history, s)
+}
+
+func pushSnapshot(stack []Snapshot, s Snapshot) []Snapshot {
+	if MAX_UNDO <= 0 {
+		return nil
+	}
+
+	if len(stack) >= MAX_UNDO {
+		stack = stack[len(stack)-MAX_UNDO+1:]
+	}
+
+	return append(stack, s)
+}
+
+func CanUndo(g *Game) bool {
+	return len(g.history) > 0
+}
+
+// Takes back the last move, including the tile it spawned
+// Returns false when there is nothing to take back
+func Undo(g *Game) bool {
+	if !CanUndo(g) {
+		return false
+	}
+
+	FinishAnimations(g)
+
+	s := g.history[len(g.history)-1]
+	g.history = g.history[:len(g.history)-1]
+	g.redo = pushSnapshot(g.redo, TakeSnapshot(g))
+
+	// Undone moves leave the recording until they are redone
+	if n := len(g.recording); n > 0 {
+		g.undone = append(g.undone, g.recording[n-1])
+		g.recording = g.recording[:n-1]
+	}
+
+	RestoreSnapshot(g, s)
+	return true
+}
+
+func CanRedo(g *Game) bool {
+	return len(g.redo) > 0
+}
+
+// Plays a move taken back by Undo again
+// Returns false when nothing was taken back since the last move
+func Redo(g *Game) bool {
+	if !CanRedo(g) {
+		return false
+	}
+
+	FinishAnimations(g)
+
+	s := g.redo[len(g.redo)-1]
+	g.redo = g.redo[:len(g.redo)-1]
+	g.history = pushSnapshot(g.history, TakeSnapshot(g))
+
+	if n := len(g.undone); n > 0 {
+		g.recording = append(g.recording, g.undone[n-1])
+		g.undone = g.undone[:n-1]
+	}
+
+	RestoreSnapshot(g, s)
+	return true
+}
+
+// Puts the values of s back onto the board, cells keep their current screen positions
+// Queued moves are dropped, they were meant for the board before
+func RestoreSnapshot(g *Game, s Snapshot) {
+	ResetBoard(&g.board)
+	g.input.Clear()
+
+	for i, row := range s.cells {
+		for j, c := range row {
+			dst := &g.board.cells[i][j]
+			dst.val = c.val
+			dst.isRendered = c.isRendered
+			dst.isBlocked = c.isBlocked
+		}
+	}
+
+	g.score = s.score
+	g.moves = s.moves
+	g.status = RUNNING
+}
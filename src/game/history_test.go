@@ -0,0 +1,50 @@
+package game
+
+import "testing"
+
+func TestUndo(t *testing.T) {
+	g := &Game{board: NewBoardAt(4, 0, 0), spawner: &ScriptedSpawner{spawns: []Spawn{{pos_x: 3, pos_y: 3, val: 4}}}}
+	g.board.cells[0][0].val, g.board.cells[0][0].isRendered = 2, true
+	g.board.cells[0][1].val, g.board.cells[0][1].isRendered = 2, true
+
+	if CanUndo(g) {
+		t.Fatal("expected nothing to undo before the first move")
+	}
+
+	PlayMove(g, RIGHT)
+	FinishAnimations(g)
+	if g.score != 4 || g.board.cells[3][3].val != 4 {
+		t.Fatalf("expected the merge and the spawn, found score %d", g.score)
+	}
+
+	if !Undo(g) {
+		t.Fatal("expected the move to be taken back")
+	}
+
+	if g.score != 0 || g.moves != 0 {
+		t.Errorf("expected score and moves to be restored, found %d and %d", g.score, g.moves)
+	}
+
+	if g.board.cells[0][0].val != 2 || g.board.cells[0][1].val != 2 || g.board.cells[0][3].val != 0 || g.board.cells[3][3].val != 0 {
+		t.Errorf("expected the board before the move, found %v", g.board.cells)
+	}
+
+	if Undo(g) {
+		t.Error("expected nothing left to undo")
+	}
+}
+
+func TestHistoryLimit(t *testing.T) {
+	g := &Game{}
+	for i := range MAX_UNDO + 5 {
+		PushHistory(g, Snapshot{moves: i})
+	}
+
+	if len(g.history) != MAX_UNDO {
+		t.Fatalf("expected %d snapshots, found %d", MAX_UNDO, len(g.history))
+	}
+
+	if g.history[0].moves != 5 {
+		t.Errorf("expected the oldest snapshots to be dropped, first is move %d", g.history[0].moves)
+	}
+}
@@ -0,0 +1,75 @@
+package game
+
+// Moves pressed while the board is still animating are kept up to this many, later ones are dropped
+var INPUT_QUEUE_SIZE = 4
+
+// Finish the running animations as soon as a move is pressed instead of waiting for them
+var FAST_FORWARD_INPUT = false
+
+// Moves waiting for the board to become idle, oldest first
+type InputQueue struct {
+	dirs []Direction
+}
+
+// Reads the input of the game into its queue
+func QueueInput(g *Game) {
+	dir, err := GetGameDirection(g)
+	if err != nil {
+		return
+	}
+
+	if FAST_FORWARD_INPUT {
+		FinishAnimations(g)
+	}
+
+	g.input.Push(dir)
+}
+
+// Plays the oldest queued move once no animation is running
+// Returns false when the board is busy or nothing is queued
+func PlayQueuedMove(g *Game) bool {
+	if HasRunningAnimation(g) {
+		return false
+	}
+
+	dir, ok := g.input.Pop()
+	if !ok {
+		return false
+	}
+
+	PlayMove(g, dir)
+	g.showHint = false
+	return true
+}
+
+// impl InputQueue
+
+// Returns false when the queue is full and d was dropped
+func (q *InputQueue) Push(d Direction) bool {
+	if len(q.dirs) >= INPUT_QUEUE_SIZE {
+		return false
+	}
+
+	q.dirs = append(q.dirs, d)
+	return true
+}
+
+func (q *InputQueue) Pop() (Direction, bool) {
+	if len(q.dirs) == 0 {
+		return UP, false
+	}
+
+	d := q.dirs[0]
+	q.dirs = q.dirs[1:]
+	return d, true
+}
+
+func (q *InputQueue) Len() int {
+	return len(q.dirs)
+}
+
+func (q *InputQueue) Clear() {
+	q.dirs = nil
+}
+
+// end
@@ -0,0 +1,52 @@
+package game
+
+import "testing"
+
+func TestInputQueueBound(t *testing.T) {
+	var q InputQueue
+	for i := range INPUT_QUEUE_SIZE {
+		if !q.Push(Direction(i % 4)) {
+			t.Fatalf("expected move %d to be queued", i)
+		}
+	}
+
+	if q.Push(LEFT) {
+		t.Error("expected a full queue to drop the move")
+	}
+
+	for i := range INPUT_QUEUE_SIZE {
+		if d, ok := q.Pop(); !ok || d != Direction(i%4) {
+			t.Errorf("expected move %d to come out in order, found %d", i, d)
+		}
+	}
+
+	if _, ok := q.Pop(); ok {
+		t.Error("expected an empty queue")
+	}
+}
+
+func TestQueuedMovesWaitForAnimations(t *testing.T) {
+	g := &Game{board: NewBoardAt(4, 0, 0), spawner: &ScriptedSpawner{spawns: []Spawn{{pos_x: 3, pos_y: 3, val: 2}, {pos_x: 3, pos_y: 2, val: 2}}}}
+	g.board.cells[0][0].val, g.board.cells[0][0].isRendered = 2, true
+
+	g.input.Push(RIGHT)
+	g.input.Push(DOWN)
+
+	if !PlayQueuedMove(g) || g.board.cells[0][3].val != 2 {
+		t.Fatal("expected the first queued move to be played on an idle board")
+	}
+
+	if PlayQueuedMove(g) {
+		t.Fatal("expected the second move to wait for the animations")
+	}
+
+	FinishAnimations(g)
+	if !PlayQueuedMove(g) {
+		t.Fatal("expected the second move once the board is idle")
+	}
+
+	FinishAnimations(g)
+	if g.board.cells[3][3].val != 4 || g.moves != 2 {
+		t.Errorf("expected both moves in order, found %d moves and %d in the corner", g.moves, g.board.cells[3][3].val)
+	}
+}
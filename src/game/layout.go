@@ -0,0 +1,188 @@
+package game
+
+import (
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+type Orientation int32
+
+const (
+	LANDSCAPE Orientation = iota
+	PORTRAIT
+)
+
+// Smallest cell size a layout shrinks to, however small the window gets
+var MIN_CELL_SIZE = 16
+
+// Screens that place their boards themselves whenever the screen size changes
+// width and height are in device pixels
+type Resizer interface {
+	Resize(width int, height int)
+}
+
+// What a screen shows around its board
+// boxes is the number of info boxes, footerLines the number of caption lines below the board
+type LayoutSpec struct {
+	boxes       int
+	footerLines int
+}
+
+// Where everything of a game goes within area
+// Landscape puts the info boxes in a column right of the board, portrait in a row above it
+type ScreenLayout struct {
+	spec        LayoutSpec
+	area        image.Rectangle
+	orientation Orientation
+	board       Vec2
+	boxes       []Vec2
+}
+
+// Last screen size reported to the App, zero until the first Layout call
+var screenSize Vec2
+
+// Size of the screen in device pixels, falls back to the window size before the first Layout call
+func ScreenSize() (int, int) {
+	if screenSize.x > 0 && screenSize.y > 0 {
+		return screenSize.x, screenSize.y
+	}
+
+	return ebiten.WindowSize()
+}
+
+// Converts the outside size handed to Layout into device pixels
+func ScaledSize(outsideWidth int, outsideHeight int) (int, int) {
+	s := ebiten.Monitor().DeviceScaleFactor()
+	return int(float64(outsideWidth) * s), int(float64(outsideHeight) * s)
+}
+
+// Areas taller than wide are portrait
+func OrientationOf(area image.Rectangle) Orientation {
+	if area.Dy() > area.Dx() {
+		return PORTRAIT
+	}
+
+	return LANDSCAPE
+}
+
+// Sets the cell size, the gap follows at a twelfth of it like the original 120 and 10
+func SetCellSize(size int) {
+	CELL_SIZE = size
+	GAP = max(1, size/12)
+}
+
+// Largest cell size that fits a board with cells per side and everything in spec into area
+func FitCellSize(spec LayoutSpec, cells int, area image.Rectangle, orientation Orientation) int {
+	n := float64(cells)
+	board := n + (n+1)/12
+	footer := 0.0
+	if spec.footerLines > 0 {
+		footer = float64(spec.footerLines+1) * 3 / 16
+	}
+
+	// Sizes in cells, with half a cell of margin on every side
+	var w, h float64
+	if orientation == LANDSCAPE {
+		w = board + 2.5
+		h = max(board+footer, float64(spec.boxes)*(1+1.0/12)+1.0/12)
+	} else {
+		w = max(board, float64(spec.boxes)*2.5-0.5)
+		h = 1 + 2.0/12 + board + footer
+	}
+
+	size := int(min(float64(area.Dx())/(w+1), float64(area.Dy())/(h+1)))
+	return max(size, MIN_CELL_SIZE)
+}
+
+// Places the board and info boxes of a board with cells per side in the middle of area
+// The cell size has to be set already, see FitCellSize
+func ComputeLayout(spec LayoutSpec, cells int, area image.Rectangle) ScreenLayout {
+	l := ScreenLayout{spec: spec, area: area, orientation: OrientationOf(area)}
+
+	boardSize := BoardSize(cells)
+	footer := 0
+	if spec.footerLines > 0 {
+		footer = (spec.footerLines + 1) * CELL_SIZE * 3 / 16
+	}
+
+	if l.orientation == LANDSCAPE {
+		contentW := boardSize + CELL_SIZE/2 + 2*CELL_SIZE
+		contentH := max(boardSize+footer, spec.boxes*(CELL_SIZE+GAP)+GAP)
+		l.board = Vec2{x: area.Min.X + (area.Dx()-contentW)/2, y: area.Min.Y + (area.Dy()-contentH)/2}
+
+		for i := range spec.boxes {
+			l.boxes = append(l.boxes, Vec2{x: l.board.x + boardSize, y: l.board.y + i*(CELL_SIZE+GAP)})
+		}
+	} else {
+		rowH := CELL_SIZE + 2*GAP
+		rowW := spec.boxes*CELL_SIZE*5/2 - CELL_SIZE/2
+		top := area.Min.Y + (area.Dy()-rowH-boardSize-footer)/2
+		left := area.Min.X + (area.Dx()-rowW)/2
+		l.board = Vec2{x: area.Min.X + (area.Dx()-boardSize)/2, y: top + rowH}
+
+		// drawInfoBox starts half a cell right of the given x
+		for i := range spec.boxes {
+			l.boxes = append(l.boxes, Vec2{x: left - CELL_SIZE/2 + i*CELL_SIZE*5/2, y: top})
+		}
+	}
+
+	return l
+}
+
+// Fits the game into area, resizing the cells and moving the board
+// Running animations are finished first since they hold on to old screen positions
+func ApplyLayout(g *Game, area image.Rectangle) {
+	FinishAnimations(g)
+
+	cells := len(g.board.cells)
+	SetCellSize(FitCellSize(g.layout.spec, cells, area, OrientationOf(area)))
+	g.layout = ComputeLayout(g.layout.spec, cells, area)
+	MoveBoard(&g.board, g.layout.board.x, g.layout.board.y)
+}
+
+// Lays the game out again in the area it was last given, e.g. after its board was replaced
+func Relayout(g *Game) {
+	if g.layout.area.Empty() {
+		w, h := ScreenSize()
+		ApplyLayout(g, image.Rect(0, 0, w, h))
+		return
+	}
+
+	ApplyLayout(g, g.layout.area)
+}
+
+// Moves the board so its top left corner is at x, y using the current cell size
+func MoveBoard(b *Board, x int, y int) {
+	size := BoardSize(len(b.cells))
+	b.bg = Background{x: x, y: y, dx: size, dy: size}
+
+	for i, row := range b.cells {
+		for j := range row {
+			b.cells[i][j].x, b.cells[i][j].y = CalculateActualCellPosition(x, y, j, i, CELL_SIZE, GAP)
+		}
+	}
+}
+
+// Position to hand to drawInfoBox for the i-th info box of the game
+// Without a layout the boxes are stacked right of the board
+func InfoBoxPosition(g *Game, i int) (int, int) {
+	if i < len(g.layout.boxes) {
+		return g.layout.boxes[i].x, g.layout.boxes[i].y
+	}
+
+	return g.board.bg.x + g.board.bg.dx, g.board.bg.y + i*(CELL_SIZE+GAP)
+}
+
+// Rectangle drawInfoBox fills for the i-th info box of the game
+func InfoBoxRect(g *Game, i int) image.Rectangle {
+	x, y := InfoBoxPosition(g, i)
+	x += CELL_SIZE / 2
+	y += GAP
+	return image.Rect(x, y, x+CELL_SIZE*2, y+CELL_SIZE)
+}
+
+// Center x and top y of the caption lines below the board
+func FooterPosition(g *Game) (int, int) {
+	return g.board.bg.x + g.board.bg.dx/2, g.board.bg.y + g.board.bg.dy
+}
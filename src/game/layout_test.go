@@ -0,0 +1,56 @@
+package game
+
+import (
+	"image"
+	"testing"
+)
+
+func TestApplyLayout(t *testing.T) {
+	defer SetCellSize(CELL_SIZE)
+
+	testCases := []struct {
+		name        string
+		area        image.Rectangle
+		orientation Orientation
+	}{
+		{name: "landscape", area: image.Rect(0, 0, 1600, 900), orientation: LANDSCAPE},
+		{name: "portrait", area: image.Rect(0, 0, 900, 1600), orientation: PORTRAIT},
+		{name: "offset half", area: image.Rect(800, 0, 1600, 900), orientation: PORTRAIT},
+		{name: "tiny", area: image.Rect(0, 0, 200, 150), orientation: LANDSCAPE},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := &Game{board: NewBoardAt(CELL_COUNT, 0, 0)}
+			g.layout.spec = LayoutSpec{boxes: 2}
+			ApplyLayout(g, tc.area)
+
+			if g.layout.orientation != tc.orientation {
+				t.Errorf("expected orientation %d, found %d", tc.orientation, g.layout.orientation)
+			}
+
+			bg := g.board.bg
+			board := image.Rect(bg.x, bg.y, bg.x+bg.dx, bg.y+bg.dy)
+			if CELL_SIZE > MIN_CELL_SIZE && !board.In(tc.area) {
+				t.Errorf("board %v does not fit into %v", board, tc.area)
+			}
+
+			last := g.board.cells[CELL_COUNT-1][CELL_COUNT-1]
+			if last.x+CELL_SIZE+GAP != bg.x+bg.dx || last.y+CELL_SIZE+GAP != bg.y+bg.dy {
+				t.Errorf("cells were not moved with the board, last cell at %d, %d", last.x, last.y)
+			}
+
+			for i := range 2 {
+				x, y := InfoBoxPosition(g, i)
+				box := image.Rect(x+CELL_SIZE/2, y+GAP, x+CELL_SIZE/2+2*CELL_SIZE, y+GAP+CELL_SIZE)
+				if box.Overlaps(board) {
+					t.Errorf("info box %d at %v overlaps the board %v", i, box, board)
+				}
+
+				if CELL_SIZE > MIN_CELL_SIZE && !box.In(tc.area) {
+					t.Errorf("info box %d at %v is outside %v", i, box, tc.area)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+// Lists the puzzles in PUZZLE_DIR and starts the selected one
+type LevelSelect struct {
+	app      *App
+	puzzles  []*Puzzle
+	selected int
+	loadErr  error
+}
+
+func NewLevelSelect(app *App) *LevelSelect {
+	ls := LevelSelect{app: app}
+	ls.puzzles, ls.loadErr = LoadPuzzles(AssetDir(PUZZLE_DIR))
+
+	return &ls
+}
+
+func (ls *LevelSelect) Update() error {
+	if Bindings().JustPressed(ACTION_QUIT) {
+		ls.app.Pop()
+		return nil
+	}
+
+	if len(ls.puzzles) == 0 {
+		return nil
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyArrowUp) {
+		ls.selected = (ls.selected - 1 + len(ls.puzzles)) % len(ls.puzzles)
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyArrowDown) {
+		ls.selected = (ls.selected + 1) % len(ls.puzzles)
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
+		ls.app.SetScreen(NewPuzzleGame(ls.app, ls, ls.puzzles[ls.selected]))
+	}
+
+	return nil
+}
+
+func (ls *LevelSelect) Draw(screen *ebiten.Image) {
+	w, h := screen.Bounds().Dx(), screen.Bounds().Dy()
+	cx := w / 2
+	lh := int(Fonts().Face(FONT_BODY).Size * 1.5)
+
+	txtOp := &text.DrawOptions{}
+	txtOp.ColorScale.ScaleWithColor(CurrentTheme().text)
+	DrawCenteredText(screen, Fonts().Face(FONT_BODY), "Select a puzzle", cx, lh, txtOp)
+
+	if ls.loadErr != nil || len(ls.puzzles) == 0 {
+		msg := "No puzzles found in " + AssetDir(PUZZLE_DIR)
+		if ls.loadErr != nil {
+			msg = ls.loadErr.Error()
+		}
+
+		txtOp = &text.DrawOptions{}
+		txtOp.ColorScale.ScaleWithColor(CurrentTheme().mutedText)
+		DrawCenteredText(screen, Fonts().Face(FONT_BODY), msg, cx, h/2, txtOp)
+		return
+	}
+
+	rowImg := ebiten.NewImage(w*2/3, lh)
+	rowImg.Fill(CurrentTheme().style.InfoBox)
+
+	for i, p := range ls.puzzles {
+		cy := lh*3 + i*lh
+
+		if i == ls.selected {
+			op := &ebiten.DrawImageOptions{}
+			op.GeoM.Translate(float64(cx-w/3), float64(cy-lh/2))
+			screen.DrawImage(rowImg, op)
+		}
+
+		txtOp = &text.DrawOptions{}
+		txtOp.ColorScale.ScaleWithColor(rowTextColor(i == ls.selected))
+		label := fmt.Sprintf("%d. %s (goal %s, %d moves)", i+1, p.name, p.GoalText(), p.maxMoves)
+		DrawCenteredText(screen, Fonts().Face(FONT_BODY), label, cx, cy, txtOp)
+	}
+}
+
+func (ls *LevelSelect) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
+	return ScaledSize(outsideWidth, outsideHeight)
+}
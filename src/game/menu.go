@@ -0,0 +1,88 @@
+package game
+
+import (
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+// Main menu, every mode of the game starts from here
+type MainMenu struct {
+	app *App
+	ui  *UI
+}
+
+func NewMainMenu(app *App) *MainMenu {
+	return &MainMenu{app: app, ui: NewUI()}
+}
+
+// Column of rows widget rows in the middle of the screen, half as wide as the screen
+func menuArea(rows int) image.Rectangle {
+	w, h := ScreenSize()
+	top := (h - UIHeight(rows)) / 2
+	return image.Rect(w/4, top, w*3/4, top+UIHeight(rows))
+}
+
+// Title above a menu column starting at top
+func drawMenuTitle(screen *ebiten.Image, title string, top int) {
+	face := Fonts().Face(FONT_TITLE)
+	txtOp := &text.DrawOptions{}
+	txtOp.ColorScale.ScaleWithColor(CurrentTheme().text)
+	DrawCenteredText(screen, face, title, screen.Bounds().Dx()/2, top-int(face.Size), txtOp)
+}
+
+// impl MainMenu
+
+func (mm *MainMenu) Update() error {
+	app, ui := mm.app, mm.ui
+
+	ui.Begin(menuArea(10))
+	if ui.Button("Classic") {
+		app.Push(NewClassicGame(app, InitGame()))
+	}
+	if ui.Button("Puzzles") {
+		app.Push(NewLevelSelect(app))
+	}
+	if ui.Button("Daily challenge") {
+		app.Push(NewDailyGame(app))
+	}
+	if ui.Button("Versus") {
+		app.Push(NewVersusGame(app, WIN_TILE, false))
+	}
+	if ui.Button("Adversarial") {
+		app.Push(NewAdversarialGame(app, false))
+	}
+	if ui.Button("Evil AI") {
+		app.Push(NewAdversarialGame(app, true))
+	}
+	if ui.Button("Editor") {
+		app.Push(NewEditor(app))
+	}
+	if ui.Button("Settings") {
+		app.Push(NewSettingsMenu(app))
+	}
+	if ui.Button("Stats") {
+		app.Push(NewStatsScreen(app))
+	}
+	quit := ui.Button("Quit")
+	ui.End()
+
+	if quit || ui.Back() {
+		app.Pop()
+	}
+
+	return nil
+}
+
+func (mm *MainMenu) Draw(screen *ebiten.Image) {
+	screen.Fill(CurrentTheme().style.Board)
+	drawMenuTitle(screen, "2048", menuArea(10).Min.Y)
+	mm.ui.Draw(screen)
+}
+
+func (mm *MainMenu) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
+	return ScaledSize(outsideWidth, outsideHeight)
+}
+
+// end
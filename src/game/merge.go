@@ -7,11 +7,11 @@ import (
 
 // TODO add tests for merges
 // Merge a horizontal slice
-// Merge direction is 0 or SIZE-1, any other value will be rejected
+// Merge direction is 0 or len(slice)-1, any other value will be rejected
 func MergeSlice(slice []Cell, to int) (int, error) {
 	mergeScore := 0
 
-	if !(to == 0 || to == CELL_COUNT-1) {
+	if !(to == 0 || to == len(slice)-1) {
 		return 0, errors.New("invalid to argument")
 	}
 
@@ -27,6 +27,8 @@ func MergeSlice(slice []Cell, to int) (int, error) {
 			if (lc.isRendered && rc.isRendered) && (lc.val == rc.val) {
 				mergeScore += lc.val * 2
 				lc.val += rc.val
+				lc.merged = true
+				lc.mergeOrigin = rc.origin
 				rc.isRendered = false
 				rc.val = 0
 			}
@@ -38,6 +40,8 @@ func MergeSlice(slice []Cell, to int) (int, error) {
 			if (lc.isRendered && rc.isRendered) && (lc.val == rc.val) {
 				mergeScore += lc.val * 2
 				rc.val += lc.val
+				rc.merged = true
+				rc.mergeOrigin = lc.origin
 				lc.isRendered = false
 				lc.val = 0
 			}
@@ -48,11 +52,11 @@ func MergeSlice(slice []Cell, to int) (int, error) {
 }
 
 // Merge a vertical (accepts a ref slice)
-// Merge direction is 0 or SIZE-1, any other value will be rejected
+// Merge direction is 0 or len(slice)-1, any other value will be rejected
 func MergeSliceRef(slice []*Cell, to int) (int, error) {
 	mergeScore := 0
 
-	if !(to == 0 || to == CELL_COUNT-1) {
+	if !(to == 0 || to == len(slice)-1) {
 		fmt.Println("invalid to argument")
 		return 0, errors.New("invalid to argument")
 	}
@@ -70,6 +74,8 @@ func MergeSliceRef(slice []*Cell, to int) (int, error) {
 			if (lc.isRendered && rc.isRendered) && (lc.val == rc.val) {
 				mergeScore += lc.val * 2
 				lc.val += rc.val
+				lc.merged = true
+				lc.mergeOrigin = rc.origin
 				rc.isRendered = false
 				rc.val = 0
 			}
@@ -81,6 +87,8 @@ func MergeSliceRef(slice []*Cell, to int) (int, error) {
 			if (lc.isRendered && rc.isRendered) && (lc.val == rc.val) {
 				mergeScore += lc.val * 2
 				rc.val += lc.val
+				rc.merged = true
+				rc.mergeOrigin = lc.origin
 				lc.isRendered = false
 				lc.val = 0
 			}
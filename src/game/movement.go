@@ -2,9 +2,6 @@ package game
 
 import (
 	"errors"
-
-	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
 )
 
 type Direction int32
@@ -16,24 +13,41 @@ const (
 	LEFT
 )
 
-func GetDirection() (Direction, error) {
-	if inpututil.IsKeyJustPressed(ebiten.KeyArrowUp) {
-		return UP, nil
+func (d Direction) String() string {
+	switch d {
+	case UP:
+		return "up"
+	case RIGHT:
+		return "right"
+	case DOWN:
+		return "down"
+	default:
+		return "left"
 	}
+}
 
-	if inpututil.IsKeyJustPressed(ebiten.KeyArrowRight) {
-		return RIGHT, nil
-	}
+func GetDirection() (Direction, error) {
+	return GetDirectionFor(Bindings())
+}
 
-	if inpututil.IsKeyJustPressed(ebiten.KeyArrowDown) {
-		return DOWN, nil
+func GetDirectionFor(keys KeyBindings) (Direction, error) {
+	moves := []struct {
+		action Action
+		dir    Direction
+	}{
+		{ACTION_UP, UP},
+		{ACTION_RIGHT, RIGHT},
+		{ACTION_DOWN, DOWN},
+		{ACTION_LEFT, LEFT},
 	}
 
-	if inpututil.IsKeyJustPressed(ebiten.KeyArrowLeft) {
-		return LEFT, nil
+	for _, m := range moves {
+		if keys.JustPressed(m.action) {
+			return m.dir, nil
+		}
 	}
 
-	return UP, errors.New("arrow keys are not pressed")
+	return UP, errors.New("direction keys are not pressed")
 }
 
 // Moves cells for a given direction
@@ -41,26 +55,40 @@ func GetDirection() (Direction, error) {
 func Move(g *Game, d Direction) (int, int) {
 	totalNumOfMovements := 0
 	totalMergeScore := 0
+
+	// Every tile starts the move from its own cell
+	for i, row := range g.board.cells {
+		for j := range row {
+			c := &g.board.cells[i][j]
+			c.origin = Vec2{x: c.x, y: c.y}
+			c.mergeOrigin = c.origin
+			c.merged = false
+		}
+	}
 	switch d {
 	case RIGHT:
 		for _, row := range g.board.cells {
-			totalNumOfMovements += ShiftRight(row)
+			for _, part := range SplitRow(row) {
+				totalNumOfMovements += ShiftRight(part)
 
-			mergeScore, err := MergeSlice(row, len(row)-1)
+				mergeScore, err := MergeSlice(part, len(part)-1)
 
-			if err == nil && mergeScore > 0 {
-				totalMergeScore += mergeScore
-				ShiftRight(row)
+				if err == nil && mergeScore > 0 {
+					totalMergeScore += mergeScore
+					ShiftRight(part)
+				}
 			}
 		}
 	case LEFT:
 		for _, row := range g.board.cells {
-			totalNumOfMovements += ShiftLeft(row)
-			mergeScore, err := MergeSlice(row, 0)
+			for _, part := range SplitRow(row) {
+				totalNumOfMovements += ShiftLeft(part)
+				mergeScore, err := MergeSlice(part, 0)
 
-			if err == nil && mergeScore > 0 {
-				totalMergeScore += mergeScore
-				ShiftLeft(row)
+				if err == nil && mergeScore > 0 {
+					totalMergeScore += mergeScore
+					ShiftLeft(part)
+				}
 			}
 		}
 	case UP:
@@ -75,12 +103,14 @@ func Move(g *Game, d Direction) (int, int) {
 			v_slice, err := TakeVerticalSlice(g.board.cells, j)
 
 			if err == nil {
-				totalNumOfMovements += ShiftUp(v_slice)
-				mergeScore, err := MergeSliceRef(v_slice, 0)
-
-				if err == nil && mergeScore > 0 {
-					totalMergeScore += mergeScore
-					ShiftUp(v_slice)
+				for _, part := range SplitSlice(v_slice) {
+					totalNumOfMovements += ShiftUp(part)
+					mergeScore, err := MergeSliceRef(part, 0)
+
+					if err == nil && mergeScore > 0 {
+						totalMergeScore += mergeScore
+						ShiftUp(part)
+					}
 				}
 			}
 		}
@@ -96,12 +126,14 @@ func Move(g *Game, d Direction) (int, int) {
 			v_slice, err := TakeVerticalSlice(g.board.cells, j)
 
 			if err == nil {
-				totalNumOfMovements += ShiftDown(v_slice)
-				mergeScore, err := MergeSliceRef(v_slice, len(v_slice)-1)
-
-				if err == nil && mergeScore > 0 {
-					totalMergeScore += mergeScore
-					ShiftDown(v_slice)
+				for _, part := range SplitSlice(v_slice) {
+					totalNumOfMovements += ShiftDown(part)
+					mergeScore, err := MergeSliceRef(part, len(part)-1)
+
+					if err == nil && mergeScore > 0 {
+						totalMergeScore += mergeScore
+						ShiftDown(part)
+					}
 				}
 			}
 		}
@@ -207,3 +239,46 @@ func ShiftDown(col []*Cell) int {
 
 	return numOfMovements
 }
+
+// Splits a row into the runs of cells between blocked cells
+// Each run shares memory with the row so it can be shifted and merged in place
+func SplitRow(row []Cell) [][]Cell {
+	parts := make([][]Cell, 0, 1)
+	start := 0
+
+	for i := range row {
+		if row[i].isBlocked {
+			if i > start {
+				parts = append(parts, row[start:i])
+			}
+			start = i + 1
+		}
+	}
+
+	if start < len(row) {
+		parts = append(parts, row[start:])
+	}
+
+	return parts
+}
+
+// Same as SplitRow for a vertical slice
+func SplitSlice(slice []*Cell) [][]*Cell {
+	parts := make([][]*Cell, 0, 1)
+	start := 0
+
+	for i, c := range slice {
+		if c.isBlocked {
+			if i > start {
+				parts = append(parts, slice[start:i])
+			}
+			start = i + 1
+		}
+	}
+
+	if start < len(slice) {
+		parts = append(parts, slice[start:])
+	}
+
+	return parts
+}
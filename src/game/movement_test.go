@@ -199,3 +199,52 @@ func compareCells(t *testing.T, testCase TestCellCmp, actualNumOfMovements int)
 		}
 	}
 }
+
+func TestMoveWithBlockedCells(t *testing.T) {
+	blocked := Cell{isBlocked: true}
+	g := &Game{board: Board{cells: [][]Cell{
+		{m(2), blocked, m(0), m(2)},
+		{m(2), m(2), blocked, m(2)},
+		{m(0), m(0), m(0), m(0)},
+		{m(4), m(0), m(0), m(0)},
+	}}}
+
+	_, mergeScore := Move(g, LEFT)
+	if mergeScore != 4 {
+		t.Errorf("expected merge score 4, found %d", mergeScore)
+	}
+
+	expected := [][]int{
+		{2, BLOCKED_VALUE, 2, 0},
+		{4, 0, BLOCKED_VALUE, 2},
+		{0, 0, 0, 0},
+		{4, 0, 0, 0},
+	}
+
+	for i, row := range g.board.cells {
+		for j, c := range row {
+			actual := c.val
+			if c.isBlocked {
+				actual = BLOCKED_VALUE
+			}
+
+			if actual != expected[i][j] {
+				t.Errorf("at row %d col %d expected %d, found %d", i, j, expected[i][j], actual)
+			}
+		}
+	}
+}
+
+func TestSplitRow(t *testing.T) {
+	row := []Cell{{isBlocked: true}, m(2), m(2), {isBlocked: true}, m(4)}
+	parts := SplitRow(row)
+
+	if len(parts) != 2 || len(parts[0]) != 2 || len(parts[1]) != 1 {
+		t.Fatalf("unexpected parts %v", parts)
+	}
+
+	parts[1][0].val = 8
+	if row[4].val != 8 {
+		t.Errorf("parts should share memory with the row")
+	}
+}
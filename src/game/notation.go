@@ -0,0 +1,197 @@
+package game
+
+import (
+	"fmt"
+	"math/bits"
+	"slices"
+	"strconv"
+	"strings"
+
+	"mkoca/2048/src/replay"
+)
+
+// Largest exponent a tile can have in notation, 2^17 is 131072
+const MAX_NOTATION_EXPONENT = 17
+
+// A board with its score, the side to play and the number of moves played, written on one line
+//
+//	12../.3#./..../...1 m 16 5
+//
+// Rows are separated by '/', every cell is the exponent of its tile in base 36 (1 is a 2, a is a 1024),
+// '.' is an empty cell and '#' a blocked one. Rows may also list values separated by commas, e.g. 2,4,.,.
+// The side is m when the tiles are moved next and s when a tile is spawned next, see AdversaryTurn
+// A game started from s spawns that tile first, so s needs an empty cell and m a tile to move
+// Side, score and moves are optional and default to m 0 0, String always writes them
+type Notation struct {
+	board [][]int
+	side  AdversaryTurn
+	score int
+	moves int
+}
+
+// The board, score and moves of the game, with the tiles to be moved next
+// Classic games spawn right after every move, so their side is always m
+// Running animations are finished first so a tile that is still spawning is on the board
+func NotationOf(g *Game) Notation {
+	FinishAnimations(g)
+	return Notation{board: cellValues(g.board.cells), side: TURN_SLIDE, score: g.score, moves: g.moves}
+}
+
+func ParseNotation(s string) (Notation, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return Notation{}, fmt.Errorf("empty notation, expected rows like 1.../..../..../...1")
+	}
+	if len(fields) > 4 {
+		return Notation{}, fmt.Errorf("too many fields, expected rows, side, score and moves, found %q", strings.Join(fields[4:], " "))
+	}
+
+	var n Notation
+	rows := strings.Split(fields[0], "/")
+	board := make([][]int, len(rows))
+	for i, row := range rows {
+		values, err := parseNotationRow(row)
+		if err != nil {
+			return Notation{}, fmt.Errorf("row %d: %w", i, err)
+		}
+		board[i] = values
+	}
+
+	pos, err := newPosition(board, nil)
+	if err != nil {
+		return Notation{}, err
+	}
+	n.board = pos.board
+
+	if len(fields) > 1 {
+		switch fields[1] {
+		case "m":
+			n.side = TURN_SLIDE
+		case "s":
+			n.side = TURN_SPAWN
+		default:
+			return Notation{}, fmt.Errorf("side must be m (move) or s (spawn), found %q", fields[1])
+		}
+	}
+
+	if n.side == TURN_SPAWN && !slices.ContainsFunc(n.board, func(row []int) bool { return slices.Contains(row, 0) }) {
+		return Notation{}, fmt.Errorf("side s needs an empty cell for the spawn")
+	}
+
+	if n.side == TURN_SLIDE && !slices.ContainsFunc(n.board, func(row []int) bool { return slices.ContainsFunc(row, replay.IsTileValue) }) {
+		return Notation{}, fmt.Errorf("side m needs a tile to move, use s to spawn the first one")
+	}
+
+	counts := []struct {
+		name string
+		dst  *int
+	}{{"score", &n.score}, {"moves", &n.moves}}
+
+	for i, c := range counts {
+		if len(fields) <= i+2 {
+			break
+		}
+
+		v, err := strconv.Atoi(fields[i+2])
+		if err != nil || v < 0 {
+			return Notation{}, fmt.Errorf("%s must be a non negative number, found %q", c.name, fields[i+2])
+		}
+		*c.dst = v
+	}
+
+	return n, nil
+}
+
+// Cells of a row written either as exponents or as comma separated values
+// Only what notation can not write back is rejected here, newPosition checks the board
+func parseNotationRow(row string) ([]int, error) {
+	if row == "" {
+		return nil, fmt.Errorf("row is empty")
+	}
+
+	if strings.Contains(row, ",") {
+		cells := strings.Split(row, ",")
+		values := make([]int, len(cells))
+		for j, cell := range cells {
+			switch cell {
+			case ".":
+			case "#":
+				values[j] = BLOCKED_VALUE
+			default:
+				v, err := strconv.Atoi(cell)
+				if err != nil || v <= 0 || v > 1<<MAX_NOTATION_EXPONENT {
+					return nil, fmt.Errorf("col %d: %q is not a tile value, expected a power of two from 2 to %d, '.' or '#'", j, cell, 1<<MAX_NOTATION_EXPONENT)
+				}
+				values[j] = v
+			}
+		}
+
+		return values, nil
+	}
+
+	values := make([]int, len(row))
+	for j, r := range row {
+		switch r {
+		case '.':
+		case '#':
+			values[j] = BLOCKED_VALUE
+		default:
+			exp, err := strconv.ParseUint(string(r), 36, 8)
+			if err != nil || exp == 0 || exp > MAX_NOTATION_EXPONENT {
+				return nil, fmt.Errorf("col %d: %q is not a tile exponent, expected 1 to 9 or a to %s, '.' or '#'", j, r, strconv.FormatInt(MAX_NOTATION_EXPONENT, 36))
+			}
+			values[j] = 1 << exp
+		}
+	}
+
+	return values, nil
+}
+
+// impl Notation
+
+// Rows as exponents, e.g. 12../.3#./..../...1 m 16 5, parsing it gives the same notation back
+func (n Notation) String() string {
+	var sb strings.Builder
+
+	for i, row := range n.board {
+		if i > 0 {
+			sb.WriteByte('/')
+		}
+
+		for _, v := range row {
+			switch {
+			case v == BLOCKED_VALUE:
+				sb.WriteByte('#')
+			case v > 0:
+				sb.WriteString(strconv.FormatInt(int64(bits.Len(uint(v))-1), 36))
+			default:
+				sb.WriteByte('.')
+			}
+		}
+	}
+
+	side := "m"
+	if n.side == TURN_SPAWN {
+		side = "s"
+	}
+
+	fmt.Fprintf(&sb, " %s %d %d", side, n.score, n.moves)
+	return sb.String()
+}
+
+// Starts a classic game from the notation with random spawns
+// With side s the due tile is spawned first, the player moves next either way
+func (n Notation) NewGame() *Game {
+	pos := Position{board: n.board}
+	g := NewGame(pos.NewBoard(), &RandomSpawner{fourChance: FOUR_CHANCE})
+	g.score, g.moves = n.score, n.moves
+
+	// ParseNotation made sure there is an empty cell for it
+	if n.side == TURN_SPAWN {
+		SpawnCell(g)
+	}
+
+	return g
+}
+
+// end
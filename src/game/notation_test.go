@@ -0,0 +1,169 @@
+package game
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// Board of a notation for tests, fails the test on invalid notation
+func mustParseNotation(t *testing.T, s string) Notation {
+	t.Helper()
+
+	n, err := ParseNotation(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return n
+}
+
+func TestNotationRoundTrip(t *testing.T) {
+	testCases := []string{
+		"..../..../..../.... s 0 0",
+		"12../.3#./..../...1 m 16 5",
+		"h.../..../..../...a s 1234 56",
+		"1./.1 m 0 0",
+		"123456/789abc/defgh./....../#....#/...... m 99 7",
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc, func(t *testing.T) {
+			if actual := mustParseNotation(t, tc).String(); actual != tc {
+				t.Errorf("expected %s back, found %s", tc, actual)
+			}
+		})
+	}
+}
+
+func TestParseNotation(t *testing.T) {
+	testCases := []struct {
+		name     string
+		s        string
+		expected Notation
+	}{
+		{
+			name:     "exponents",
+			s:        "1a../..../..../...h",
+			expected: Notation{board: [][]int{{2, 1024, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 131072}}, side: TURN_SLIDE},
+		},
+		{
+			name:     "values",
+			s:        "2,2048/.,# s 8",
+			expected: Notation{board: [][]int{{2, 2048}, {0, BLOCKED_VALUE}}, side: TURN_SPAWN, score: 8},
+		},
+		{
+			name:     "mixed rows",
+			s:        "2,.,./.2./... m 4 2",
+			expected: Notation{board: [][]int{{2, 0, 0}, {0, 4, 0}, {0, 0, 0}}, side: TURN_SLIDE, score: 4, moves: 2},
+		},
+		{
+			name:     "extra spaces",
+			s:        "  ../..   s  ",
+			expected: Notation{board: [][]int{{0, 0}, {0, 0}}, side: TURN_SPAWN},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := mustParseNotation(t, tc.s)
+			if !slices.EqualFunc(actual.board, tc.expected.board, slices.Equal) || actual.side != tc.expected.side || actual.score != tc.expected.score || actual.moves != tc.expected.moves {
+				t.Errorf("expected %s, found %s", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestParseNotationErrors(t *testing.T) {
+	testCases := []struct {
+		s        string
+		expected string
+	}{
+		{s: "", expected: "empty notation"},
+		{s: "....", expected: "board needs at least 2 rows"},
+		{s: "../...", expected: "board is not square, row 1 has 3 cells"},
+		{s: "../", expected: "row 1: row is empty"},
+		{s: ".z/..", expected: `row 0: col 1: 'z' is not a tile exponent, expected 1 to 9 or a to h`},
+		{s: "0./..", expected: `row 0: col 0: '0' is not a tile exponent`},
+		{s: "0,./..", expected: `row 0: col 0: "0" is not a tile value`},
+		{s: "3,./..", expected: "invalid tile value 3 at row 0 col 0"},
+		{s: "1./.. x", expected: `side must be m (move) or s (spawn), found "x"`},
+		{s: "11/#1 s", expected: "side s needs an empty cell"},
+		{s: "..../..../..../.... m 0 0", expected: "side m needs a tile to move"},
+		{s: "#./.. m", expected: "side m needs a tile to move"},
+		{s: "../..", expected: "side m needs a tile to move"},
+		{s: "1./.. m -1", expected: `score must be a non negative number, found "-1"`},
+		{s: "1./.. m 0 many", expected: `moves must be a non negative number, found "many"`},
+		{s: "1./.. m 0 0 extra", expected: "too many fields"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.s, func(t *testing.T) {
+			_, err := ParseNotation(tc.s)
+			if err == nil || !strings.Contains(err.Error(), tc.expected) {
+				t.Errorf("expected an error containing %q, found %v", tc.expected, err)
+			}
+		})
+	}
+}
+
+func TestNotationOfGame(t *testing.T) {
+	const s = "1..1/..#./..2./.... m 12 3"
+	g := mustParseNotation(t, s).NewGame()
+
+	if actual := NotationOf(g).String(); actual != s {
+		t.Fatalf("expected the game to start from %s, found %s", s, actual)
+	}
+
+	// The next spawn goes to the bottom right corner so the rows that move stay predictable
+	g.spawner = &ScriptedSpawner{spawns: []Spawn{{pos_x: 3, pos_y: 3, val: 2}}}
+	PlayMove(g, LEFT)
+	FinishAnimations(g)
+
+	expected := "2.../..#./2.../...1 m 16 4"
+	if actual := NotationOf(g).String(); actual != expected {
+		t.Errorf("expected the 2s to merge and the 4 to slide left into %s, found %s", expected, actual)
+	}
+}
+
+func TestNotationOfRightAfterMove(t *testing.T) {
+	g := mustParseNotation(t, "11../..../..../....").NewGame()
+	g.spawner = &ScriptedSpawner{spawns: []Spawn{{pos_x: 3, pos_y: 3, val: 4}}}
+	PlayMove(g, LEFT)
+
+	expected := "2.../..../..../...2 m 4 1"
+	if actual := NotationOf(g).String(); actual != expected {
+		t.Errorf("expected the spawned 4 in %s, found %s", expected, actual)
+	}
+}
+
+func TestNotationSide(t *testing.T) {
+	testCases := []struct {
+		name     string
+		s        string
+		expected int
+	}{
+		{name: "move next", s: "1.../..../..../.... m", expected: 1},
+		{name: "spawn next", s: "1.../..../..../.... s", expected: 2},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := mustParseNotation(t, tc.s).NewGame()
+			FinishAnimations(g)
+
+			actual := 0
+			for _, row := range cellValues(g.board.cells) {
+				for _, v := range row {
+					if v > 0 {
+						actual++
+					}
+				}
+			}
+
+			if actual != tc.expected || g.moves != 0 {
+				t.Errorf("expected %d tiles before the first move, found %d after %d moves", tc.expected, actual, g.moves)
+			}
+		})
+	}
+}
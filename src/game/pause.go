@@ -0,0 +1,70 @@
+package game
+
+import (
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Menu over a paused game, the game stays frozen until it is resumed
+type PauseMenu struct {
+	app     *App
+	ui      *UI
+	game    *Game
+	restart func()
+	// Goes back to the main menu
+	leave func()
+}
+
+func NewPauseMenu(app *App, g *Game, restart func(), leave func()) *PauseMenu {
+	return &PauseMenu{app: app, ui: NewUI(), game: g, restart: restart, leave: leave}
+}
+
+func (pm *PauseMenu) resume() {
+	pm.app.Pop()
+	ResumeGame(pm.game)
+}
+
+func (pm *PauseMenu) IsOverlay() bool {
+	return true
+}
+
+func (pm *PauseMenu) Update() error {
+	AssignGamepads([]*Game{pm.game})
+
+	app, ui := pm.app, pm.ui
+	w, h := ScreenSize()
+	screen := image.Rect(0, 0, w, h)
+
+	ui.Begin(screen)
+	ui.BeginModal(screen, "Paused", 4)
+	resume := ui.Button("Resume")
+	if ui.Button("Restart") {
+		app.Push(NewConfirmDialog(app, "Abandon this game?", func() {
+			pm.resume()
+			pm.restart()
+		}))
+	}
+	if ui.Button("Settings") {
+		app.Push(NewSettingsMenu(app))
+	}
+	if ui.Button("Main menu") {
+		app.Push(NewConfirmDialog(app, "Leave this game?", pm.leave))
+	}
+	ui.EndModal()
+	ui.End()
+
+	if resume || ui.Back() || Bindings().JustPressed(ACTION_PAUSE) || pm.game.gamepad.JustPressed(PAD_MENU) {
+		pm.resume()
+	}
+
+	return nil
+}
+
+func (pm *PauseMenu) Draw(screen *ebiten.Image) {
+	pm.ui.Draw(screen)
+}
+
+func (pm *PauseMenu) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
+	return ScaledSize(outsideWidth, outsideHeight)
+}
@@ -0,0 +1,137 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"mkoca/2048/src/replay"
+)
+
+var POSITION_DIR = "assets/positions"
+
+// Board value used in files for cells that tiles can not enter
+const BLOCKED_VALUE = replay.BLOCKED_VALUE
+
+// A board layout and the spawns that follow it
+// board holds the values row by row, 0 means empty and BLOCKED_VALUE means blocked
+type Position struct {
+	board  [][]int
+	spawns []Spawn
+}
+
+type spawnEntry struct {
+	Row   int `json:"row"`
+	Col   int `json:"col"`
+	Value int `json:"value"`
+}
+
+// On disk representation of a starting position
+type positionFile struct {
+	Board  [][]int      `json:"board"`
+	Spawns []spawnEntry `json:"spawns"`
+}
+
+func ParsePosition(data []byte) (*Position, error) {
+	var f positionFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("invalid position file: %w", err)
+	}
+
+	pos, err := newPosition(f.Board, f.Spawns)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pos, nil
+}
+
+func LoadPosition(path string) (*Position, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	pos, err := ParsePosition(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return pos, nil
+}
+
+func newPosition(board [][]int, spawns []spawnEntry) (Position, error) {
+	if err := replay.CheckBoard(board); err != nil {
+		return Position{}, err
+	}
+
+	size := len(board)
+	pos := Position{board: board}
+
+	for i, s := range spawns {
+		if s.Row < 0 || s.Row >= size || s.Col < 0 || s.Col >= size {
+			return Position{}, fmt.Errorf("spawn %d is outside of the board", i)
+		}
+
+		if !replay.IsTileValue(s.Value) {
+			return Position{}, fmt.Errorf("spawn %d has invalid value %d", i, s.Value)
+		}
+
+		// A blocked cell never frees up, the spawn could only ever be skipped
+		if board[s.Row][s.Col] == BLOCKED_VALUE {
+			return Position{}, fmt.Errorf("spawn %d is on a blocked cell", i)
+		}
+
+		pos.spawns = append(pos.spawns, Spawn{pos_x: s.Row, pos_y: s.Col, val: s.Value})
+	}
+
+	return pos, nil
+}
+
+// impl Position
+
+func (pos *Position) Marshal() ([]byte, error) {
+	f := positionFile{Board: pos.board, Spawns: pos.spawnEntries()}
+	return json.MarshalIndent(f, "", "  ")
+}
+
+// Builds a board centered on the window with the position's tiles and blocked cells
+func (pos *Position) NewBoard() Board {
+	b := NewBoard(len(pos.board))
+	for i, row := range pos.board {
+		for j, val := range row {
+			c := &b.cells[i][j]
+			c.isBlocked = val == BLOCKED_VALUE
+			c.isRendered = val > 0
+			if c.isRendered {
+				c.val = val
+			}
+		}
+	}
+
+	return b
+}
+
+// Replays the position's spawns, then hands over to fallback
+func (pos *Position) NewSpawner(fallback Spawner) *ScriptedSpawner {
+	spawns := make([]Spawn, len(pos.spawns))
+	copy(spawns, pos.spawns)
+
+	return &ScriptedSpawner{spawns: spawns, fallback: fallback}
+}
+
+// Starts a classic game from the position
+func (pos *Position) NewGame() *Game {
+	return NewGame(pos.NewBoard(), pos.NewSpawner(&RandomSpawner{}))
+}
+
+func (pos *Position) spawnEntries() []spawnEntry {
+	entries := make([]spawnEntry, len(pos.spawns))
+	for i, s := range pos.spawns {
+		entries[i] = spawnEntry{Row: s.pos_x, Col: s.pos_y, Value: s.val}
+	}
+
+	return entries
+}
+
+// end
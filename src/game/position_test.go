@@ -0,0 +1,74 @@
+package game
+
+import "testing"
+
+func TestPositionRoundTrip(t *testing.T) {
+	input := `{"board": [[2, -1, 0], [0, 4, 0], [0, 0, 8]], "spawns": [{"row": 0, "col": 2, "value": 4}]}`
+
+	pos, err := ParsePosition([]byte(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := pos.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := ParsePosition(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, row := range pos.board {
+		for j, val := range row {
+			if parsed.board[i][j] != val {
+				t.Errorf("at row %d col %d expected %d, found %d", i, j, val, parsed.board[i][j])
+			}
+		}
+	}
+
+	if len(parsed.spawns) != 1 || parsed.spawns[0] != pos.spawns[0] {
+		t.Errorf("expected spawns %v, found %v", pos.spawns, parsed.spawns)
+	}
+}
+
+func TestPuzzleRoundTrip(t *testing.T) {
+	p := Puzzle{
+		name:     "round trip",
+		start:    Position{board: [][]int{{2, 2}, {0, BLOCKED_VALUE}}},
+		goal:     PuzzleGoal{goalType: GOAL_TILE, value: 8},
+		maxMoves: 5,
+		stars:    [2]int{2, 3},
+	}
+
+	data, err := p.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := ParsePuzzle(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if parsed.name != p.name || parsed.goal != p.goal || parsed.maxMoves != p.maxMoves || parsed.stars != p.stars {
+		t.Errorf("expected %+v, found %+v", p, parsed)
+	}
+}
+
+func TestPositionSpawnerFallback(t *testing.T) {
+	pos := Position{board: [][]int{{0, 0}, {0, 0}}, spawns: []Spawn{{pos_x: 1, pos_y: 0, val: 4}}}
+	cells := [][]Cell{{m(0), m(0)}, {m(0), m(0)}}
+	spawner := pos.NewSpawner(&RandomSpawner{})
+
+	s, err := spawner.NextSpawn(cells)
+	if err != nil || s.val != 4 {
+		t.Errorf("expected the scripted spawn first, found %+v, %v", s, err)
+	}
+
+	_, err = spawner.NextSpawn(cells)
+	if err != nil {
+		t.Errorf("expected the fallback spawner to take over, found %v", err)
+	}
+}
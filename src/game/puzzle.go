@@ -0,0 +1,332 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"mkoca/2048/src/replay"
+	"mkoca/2048/src/sound"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+var PUZZLE_DIR = "assets/puzzles"
+
+type GoalType int32
+
+const (
+	GOAL_TILE        GoalType = iota // reach a tile with the goal value
+	GOAL_SINGLE_TILE                 // clear the board down to a single tile
+)
+
+type PuzzleGoal struct {
+	goalType GoalType
+	value    int
+}
+
+// A level with a fixed starting position and spawn sequence
+// stars holds the maximum moves allowed for 3 and 2 stars, any other solve gets 1 star
+type Puzzle struct {
+	name     string
+	start    Position
+	goal     PuzzleGoal
+	maxMoves int
+	stars    [2]int
+}
+
+type puzzleGoalEntry struct {
+	Type  string `json:"type"`
+	Value int    `json:"value,omitempty"`
+}
+
+// On disk representation of a puzzle
+type puzzleFile struct {
+	Name     string          `json:"name"`
+	Board    [][]int         `json:"board"`
+	Spawns   []spawnEntry    `json:"spawns"`
+	Goal     puzzleGoalEntry `json:"goal"`
+	MaxMoves int             `json:"max_moves"`
+	Stars    [2]int          `json:"stars"`
+}
+
+// back is the screen shown when the player leaves the puzzle
+type PuzzleGame struct {
+	app    *App
+	back   ebiten.Game
+	puzzle *Puzzle
+	game   *Game
+}
+
+func ParsePuzzle(data []byte) (*Puzzle, error) {
+	var f puzzleFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("invalid puzzle file: %w", err)
+	}
+
+	p, err := newPuzzle(f)
+	if err != nil && f.Name != "" {
+		return nil, fmt.Errorf("puzzle %q: %w", f.Name, err)
+	}
+
+	return p, err
+}
+
+func newPuzzle(f puzzleFile) (*Puzzle, error) {
+	start, err := newPosition(f.Board, f.Spawns)
+	if err != nil {
+		return nil, err
+	}
+
+	p := Puzzle{name: f.Name, start: start, maxMoves: f.MaxMoves, stars: f.Stars}
+
+	switch f.Goal.Type {
+	case "tile":
+		if !replay.IsTileValue(f.Goal.Value) {
+			return nil, fmt.Errorf("invalid goal tile %d", f.Goal.Value)
+		}
+		p.goal = PuzzleGoal{goalType: GOAL_TILE, value: f.Goal.Value}
+	case "single":
+		p.goal = PuzzleGoal{goalType: GOAL_SINGLE_TILE}
+	default:
+		return nil, fmt.Errorf("unknown goal type %q", f.Goal.Type)
+	}
+
+	if p.maxMoves < 1 {
+		return nil, errors.New("max_moves must be at least 1")
+	}
+
+	// Missing thresholds give 3 stars to any solve
+	for i := range p.stars {
+		if p.stars[i] <= 0 {
+			p.stars[i] = p.maxMoves
+		}
+	}
+
+	if p.stars[0] > p.stars[1] || p.stars[1] > p.maxMoves {
+		return nil, fmt.Errorf("stars %v must be ascending and at most max_moves", f.Stars)
+	}
+
+	return &p, nil
+}
+
+func LoadPuzzle(path string) (*Puzzle, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := ParsePuzzle(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	if p.name == "" {
+		p.name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+
+	return p, nil
+}
+
+// Loads every .json puzzle in dir, ordered by file name
+func LoadPuzzles(dir string) ([]*Puzzle, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(paths)
+	puzzles := make([]*Puzzle, 0, len(paths))
+
+	for _, path := range paths {
+		p, err := LoadPuzzle(path)
+		if err != nil {
+			return nil, err
+		}
+
+		puzzles = append(puzzles, p)
+	}
+
+	return puzzles, nil
+}
+
+// impl Puzzle
+
+func (p *Puzzle) IsGoalReached(cells [][]Cell) bool {
+	switch p.goal.goalType {
+	case GOAL_TILE:
+		for _, row := range cells {
+			for _, c := range row {
+				if c.isRendered && c.val >= p.goal.value {
+					return true
+				}
+			}
+		}
+	case GOAL_SINGLE_TILE:
+		count := 0
+		for _, row := range cells {
+			for _, c := range row {
+				if c.isRendered {
+					count++
+				}
+			}
+		}
+
+		return count == 1
+	}
+
+	return false
+}
+
+// Star rating for a solve that used the given number of moves
+func (p *Puzzle) Stars(moves int) int {
+	if moves <= p.stars[0] {
+		return 3
+	}
+
+	if moves <= p.stars[1] {
+		return 2
+	}
+
+	return 1
+}
+
+func (p *Puzzle) GoalText() string {
+	if p.goal.goalType == GOAL_SINGLE_TILE {
+		return "ONE TILE"
+	}
+
+	return strconv.Itoa(p.goal.value)
+}
+
+func (p *Puzzle) Marshal() ([]byte, error) {
+	f := puzzleFile{
+		Name:     p.name,
+		Board:    p.start.board,
+		Spawns:   p.start.spawnEntries(),
+		MaxMoves: p.maxMoves,
+		Stars:    p.stars,
+	}
+
+	switch p.goal.goalType {
+	case GOAL_TILE:
+		f.Goal = puzzleGoalEntry{Type: "tile", Value: p.goal.value}
+	case GOAL_SINGLE_TILE:
+		f.Goal = puzzleGoalEntry{Type: "single"}
+	}
+
+	return json.MarshalIndent(f, "", "  ")
+}
+
+// Builds a fresh game at the puzzle's starting position
+// Once the spawn script is exhausted no more tiles are spawned
+func (p *Puzzle) NewGame() *Game {
+	return NewGame(p.start.NewBoard(), p.start.NewSpawner(nil))
+}
+
+// end
+
+// impl PuzzleGame
+
+func NewPuzzleGame(app *App, back ebiten.Game, p *Puzzle) *PuzzleGame {
+	pg := PuzzleGame{app: app, back: back, puzzle: p}
+	pg.restart()
+
+	return &pg
+}
+
+// Starts the puzzle over with goal, moves and score next to the board
+func (pg *PuzzleGame) restart() {
+	pg.game = pg.puzzle.NewGame()
+	pg.game.layout.spec = LayoutSpec{boxes: 3}
+	Relayout(pg.game)
+}
+
+func (pg *PuzzleGame) Update() error {
+	g := pg.game
+
+	AssignGamepads([]*Game{g})
+
+	if Bindings().JustPressed(ACTION_QUIT) || g.gamepad.JustPressed(PAD_MENU) {
+		pg.app.SetScreen(pg.back)
+		return nil
+	}
+
+	if g.gamepad.JustPressed(PAD_NEW_GAME) {
+		pg.restart()
+		return nil
+	}
+
+	AdvanceAnimations(g)
+
+	switch g.status {
+	case RUNNING:
+		QueueInput(g)
+		if HasRunningAnimation(g) {
+			break
+		}
+
+		if pg.puzzle.IsGoalReached(g.board.cells) {
+			g.status = FINISHED
+			sound.Play(sound.WIN, 0)
+			break
+		}
+
+		if g.moves >= pg.puzzle.maxMoves || IsGameOver(g.board.cells) {
+			g.status = GAME_OVER
+			sound.Play(sound.GAME_OVER, 0)
+			break
+		}
+
+		PlayQueuedMove(g)
+	case FINISHED, GAME_OVER:
+		if inpututil.IsKeyJustPressed(ebiten.KeyR) {
+			pg.restart()
+		} else if inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
+			pg.app.SetScreen(pg.back)
+		}
+	}
+
+	return nil
+}
+
+func (pg *PuzzleGame) Draw(screen *ebiten.Image) {
+	g := pg.game
+
+	drawBackground(g, screen)
+	drawBoard(g, screen)
+
+	x, y := InfoBoxPosition(g, 0)
+	drawInfoBox(g, screen, x, y, "GOAL", pg.puzzle.GoalText())
+	x, y = InfoBoxPosition(g, 1)
+	drawInfoBox(g, screen, x, y, "MOVES", fmt.Sprintf("%d/%d", g.moves, pg.puzzle.maxMoves))
+	x, y = InfoBoxPosition(g, 2)
+	drawScoreboard(g, screen, x, y)
+
+	switch g.status {
+	case FINISHED:
+		drawOverlay(g, screen)
+		stars := pg.puzzle.Stars(g.moves)
+		drawAfterGameText(g, screen, fmt.Sprintf("Solved! %d/3 stars", stars), "R to retry, Enter to go back")
+	case GAME_OVER:
+		drawOverlay(g, screen)
+		drawAfterGameText(g, screen, "Puzzle failed!", "R to retry, Enter to go back")
+	}
+}
+
+func (pg *PuzzleGame) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
+	return ScaledSize(outsideWidth, outsideHeight)
+}
+
+func (pg *PuzzleGame) Resize(width int, height int) {
+	ApplyLayout(pg.game, image.Rect(0, 0, width, height))
+}
+
+// end
@@ -0,0 +1,174 @@
+package game
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type PuzzleParseTest struct {
+	name        string
+	input       string
+	expectedErr bool
+}
+
+type PuzzleSolveTest struct {
+	name          string
+	file          string
+	moves         []Direction
+	expectedStars int
+}
+
+func TestParsePuzzle(t *testing.T) {
+	testCases := []PuzzleParseTest{
+		{
+			name:        "valid tile goal",
+			input:       `{"board": [[2, 2], [0, 0]], "goal": {"type": "tile", "value": 8}, "max_moves": 3}`,
+			expectedErr: false,
+		},
+		{
+			name:        "valid single tile goal with spawns",
+			input:       `{"board": [[2, 2], [0, 0]], "spawns": [{"row": 1, "col": 1, "value": 4}], "goal": {"type": "single"}, "max_moves": 3}`,
+			expectedErr: false,
+		},
+		{
+			name:        "board is not square",
+			input:       `{"board": [[2, 2, 0], [0, 0]], "goal": {"type": "single"}, "max_moves": 3}`,
+			expectedErr: true,
+		},
+		{
+			name:        "tile value is not a power of two",
+			input:       `{"board": [[2, 3], [0, 0]], "goal": {"type": "single"}, "max_moves": 3}`,
+			expectedErr: true,
+		},
+		{
+			name:        "spawn outside of board",
+			input:       `{"board": [[2, 2], [0, 0]], "spawns": [{"row": 2, "col": 0, "value": 2}], "goal": {"type": "single"}, "max_moves": 3}`,
+			expectedErr: true,
+		},
+		{
+			name:        "spawn on a blocked cell",
+			input:       `{"board": [[2, 2], [0, -1]], "spawns": [{"row": 1, "col": 1, "value": 2}], "goal": {"type": "single"}, "max_moves": 3}`,
+			expectedErr: true,
+		},
+		{
+			name:        "star thresholds descending",
+			input:       `{"board": [[2, 2], [0, 0]], "goal": {"type": "single"}, "max_moves": 5, "stars": [4, 2]}`,
+			expectedErr: true,
+		},
+		{
+			name:        "star threshold above move limit",
+			input:       `{"board": [[2, 2], [0, 0]], "goal": {"type": "single"}, "max_moves": 3, "stars": [2, 4]}`,
+			expectedErr: true,
+		},
+		{
+			name:        "only the 3 star threshold",
+			input:       `{"board": [[2, 2], [0, 0]], "goal": {"type": "single"}, "max_moves": 3, "stars": [2, 0]}`,
+			expectedErr: false,
+		},
+		{
+			name:        "unknown goal",
+			input:       `{"board": [[2, 2], [0, 0]], "goal": {"type": "score"}, "max_moves": 3}`,
+			expectedErr: true,
+		},
+		{
+			name:        "missing move limit",
+			input:       `{"board": [[2, 2], [0, 0]], "goal": {"type": "single"}}`,
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParsePuzzle([]byte(tc.input))
+			if (err != nil) != tc.expectedErr {
+				t.Errorf("expected error %t, found %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestParsePuzzleNamesPuzzle(t *testing.T) {
+	_, err := ParsePuzzle([]byte(`{"name": "Broken", "board": [[2, 2], [0, 0]], "goal": {"type": "single"}}`))
+	if err == nil || !strings.Contains(err.Error(), `"Broken"`) {
+		t.Errorf("expected an error naming the puzzle, found %v", err)
+	}
+}
+
+func TestPuzzleStars(t *testing.T) {
+	p := Puzzle{maxMoves: 6, stars: [2]int{3, 4}}
+	expected := map[int]int{2: 3, 3: 3, 4: 2, 5: 1, 6: 1}
+
+	for moves, expectedStars := range expected {
+		if actualStars := p.Stars(moves); actualStars != expectedStars {
+			t.Errorf("expected %d stars for %d moves, found %d", expectedStars, moves, actualStars)
+		}
+	}
+}
+
+func TestPuzzleGoal(t *testing.T) {
+	tile := Puzzle{goal: PuzzleGoal{goalType: GOAL_TILE, value: 8}}
+	single := Puzzle{goal: PuzzleGoal{goalType: GOAL_SINGLE_TILE}}
+
+	cells := [][]Cell{{m(4), m(4)}, {m(0), m(0)}}
+	if tile.IsGoalReached(cells) || single.IsGoalReached(cells) {
+		t.Errorf("goal should not be reached")
+	}
+
+	cells = [][]Cell{{m(8), m(0)}, {m(0), m(0)}}
+	if !tile.IsGoalReached(cells) || !single.IsGoalReached(cells) {
+		t.Errorf("goal should be reached")
+	}
+}
+
+func TestScriptedSpawner(t *testing.T) {
+	cells := [][]Cell{{m(2), m(0)}, {m(0), m(0)}}
+	spawner := ScriptedSpawner{spawns: []Spawn{{pos_x: 1, pos_y: 1, val: 4}, {pos_x: 0, pos_y: 0, val: 2}}}
+
+	s, err := spawner.NextSpawn(cells)
+	if err != nil || s.pos_x != 1 || s.pos_y != 1 || s.val != 4 {
+		t.Errorf("unexpected first spawn %+v, %v", s, err)
+	}
+
+	_, err = spawner.NextSpawn(cells)
+	if err == nil {
+		t.Errorf("spawning on an occupied cell should fail")
+	}
+
+	_, err = spawner.NextSpawn(cells)
+	if err == nil {
+		t.Errorf("exhausted script should fail")
+	}
+}
+
+func TestBundledPuzzles(t *testing.T) {
+	testCases := []PuzzleSolveTest{
+		{name: "first merge", file: "01-first-merge.json", moves: []Direction{LEFT, LEFT}, expectedStars: 3},
+		{name: "clean sweep", file: "02-clean-sweep.json", moves: []Direction{LEFT, UP}, expectedStars: 3},
+		{name: "corner", file: "03-corner.json", moves: []Direction{UP, LEFT, LEFT, LEFT}, expectedStars: 3},
+		{name: "tight squeeze", file: "04-tight-squeeze.json", moves: []Direction{UP, LEFT, LEFT}, expectedStars: 3},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := LoadPuzzle(filepath.Join("..", "..", PUZZLE_DIR, tc.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			g := &Game{board: p.start.NewBoard(), spawner: p.start.NewSpawner(nil)}
+			for _, d := range tc.moves {
+				PlayMove(g, d)
+				FinishAnimations(g)
+			}
+
+			if !p.IsGoalReached(g.board.cells) {
+				t.Errorf("goal is not reached after %d moves", g.moves)
+			}
+
+			if actualStars := p.Stars(g.moves); actualStars != tc.expectedStars {
+				t.Errorf("expected %d stars, found %d", tc.expectedStars, actualStars)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package game
+
+import (
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+// Lists every action with its keys, Enter captures the next key pressed for the selected one
+// Tab switches presets, Escape saves to the settings file and closes the screen
+// Navigation always uses the arrow keys, Enter and Escape so a broken binding can be fixed
+type KeyBindingScreen struct {
+	app       *App
+	preset    string
+	bindings  KeyBindings
+	selected  int
+	capturing bool
+	message   string
+}
+
+func NewKeyBindingScreen(app *App) *KeyBindingScreen {
+	s, err := LoadSettings()
+	if err != nil {
+		log.Println("settings are not available:", err)
+	}
+
+	return &KeyBindingScreen{app: app, preset: s.KeyPreset, bindings: Bindings().Clone()}
+}
+
+func (ks *KeyBindingScreen) Update() error {
+	if ks.capturing {
+		ks.capture()
+		return nil
+	}
+
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyEscape):
+		ks.save()
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowUp):
+		ks.selected = (ks.selected - 1 + len(ACTIONS)) % len(ACTIONS)
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowDown):
+		ks.selected = (ks.selected + 1) % len(ACTIONS)
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnter):
+		ks.capturing = true
+		ks.message = ""
+	case inpututil.IsKeyJustPressed(ebiten.KeyTab):
+		i := slices.Index(KEY_PRESET_NAMES, ks.preset)
+		ks.preset = KEY_PRESET_NAMES[(i+1)%len(KEY_PRESET_NAMES)]
+		ks.bindings = KEY_PRESETS[ks.preset].Clone()
+		ks.message = "Switched to the " + ks.preset + " preset"
+	}
+
+	return nil
+}
+
+func (ks *KeyBindingScreen) CapturesKeys() bool {
+	return ks.capturing
+}
+
+// Binds the first key pressed to the selected action, Escape keeps the old keys
+func (ks *KeyBindingScreen) capture() {
+	keys := inpututil.AppendJustPressedKeys(nil)
+	if len(keys) == 0 {
+		return
+	}
+
+	ks.capturing = false
+	if keys[0] == ebiten.KeyEscape && ACTIONS[ks.selected] != ACTION_QUIT {
+		return
+	}
+
+	ks.bindings[ACTIONS[ks.selected]] = []ebiten.Key{keys[0]}
+}
+
+// Stores the bindings and closes the screen
+func (ks *KeyBindingScreen) save() {
+	s, err := LoadSettings()
+	if err != nil {
+		log.Println("settings are not available:", err)
+	}
+
+	s.KeyPreset = ks.preset
+	s.Keys = ks.bindings.Overrides(ks.preset)
+	SetBindings(ks.bindings)
+
+	if err := SaveSettings(s); err != nil {
+		log.Println("failed to save the key bindings:", err)
+	}
+
+	ks.app.Pop()
+}
+
+func (ks *KeyBindingScreen) Draw(screen *ebiten.Image) {
+	w, h := screen.Bounds().Dx(), screen.Bounds().Dy()
+	cx := w / 2
+	body := Fonts().Face(FONT_BODY)
+	lh := int(body.Size * 1.5)
+
+	txtOp := &text.DrawOptions{}
+	txtOp.ColorScale.ScaleWithColor(CurrentTheme().text)
+	DrawCenteredText(screen, body, fmt.Sprintf("Key bindings (%s preset)", ks.preset), cx, lh, txtOp)
+
+	rowImg := ebiten.NewImage(w*2/3, lh)
+	rowImg.Fill(CurrentTheme().style.InfoBox)
+
+	for i, action := range ACTIONS {
+		cy := lh*3 + i*lh
+
+		if i == ks.selected {
+			op := &ebiten.DrawImageOptions{}
+			op.GeoM.Translate(float64(cx-w/3), float64(cy-lh/2))
+			screen.DrawImage(rowImg, op)
+		}
+
+		keys := ks.bindings.Label(action)
+		if i == ks.selected && ks.capturing {
+			keys = "press a key..."
+		}
+
+		txtOp = &text.DrawOptions{}
+		txtOp.ColorScale.ScaleWithColor(rowTextColor(i == ks.selected))
+		DrawCenteredText(screen, body, fmt.Sprintf("%s: %s", ACTION_NAMES[action], keys), cx, cy, txtOp)
+	}
+
+	lines := []string{"Enter rebinds, Tab switches presets, Escape saves and goes back"}
+	if ks.message != "" {
+		lines = append(lines, ks.message)
+	}
+
+	conflicts := ks.bindings.Conflicts()
+	keys := slices.Sorted(maps.Keys(conflicts))
+	for _, key := range keys {
+		actions := conflicts[key]
+		names := make([]string, len(actions))
+		for i, a := range actions {
+			names[i] = ACTION_NAMES[a]
+		}
+		lines = append(lines, fmt.Sprintf("%s is bound to %s", key, strings.Join(names, " and ")))
+	}
+
+	caption := Fonts().Face(FONT_CAPTION)
+	clh := int(caption.Size * 1.5)
+	for i, line := range lines {
+		txtOp = &text.DrawOptions{}
+		txtOp.ColorScale.ScaleWithColor(CurrentTheme().mutedText)
+		DrawCenteredText(screen, caption, line, cx, h-clh*(len(lines)-i), txtOp)
+	}
+}
+
+func (ks *KeyBindingScreen) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
+	return ScaledSize(outsideWidth, outsideHeight)
+}
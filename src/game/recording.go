@@ -0,0 +1,35 @@
+package game
+
+import "mkoca/2048/src/replay"
+
+// The moves played so far and the current board, drawn in the current theme
+// Running animations are finished first so a tile that is still spawning is part of the last board
+func RecordGame(g *Game) replay.Recording {
+	FinishAnimations(g)
+
+	frames := make([]replay.Frame, 0, len(g.recording)+1)
+	for _, s := range g.recording {
+		frames = append(frames, replay.Frame{Board: cellValues(s.cells), Score: s.score, Move: s.move.String()})
+	}
+	frames = append(frames, replay.Frame{Board: cellValues(g.board.cells), Score: g.score})
+
+	return replay.NewRecording(frames, ImageStyle(CurrentTheme()))
+}
+
+// Values of the cells row by row, BLOCKED_VALUE for blocked cells
+func cellValues(cells [][]Cell) [][]int {
+	board := make([][]int, len(cells))
+	for i, row := range cells {
+		board[i] = make([]int, len(row))
+		for j, c := range row {
+			switch {
+			case c.isBlocked:
+				board[i][j] = BLOCKED_VALUE
+			case c.isRendered:
+				board[i][j] = c.val
+			}
+		}
+	}
+
+	return board
+}
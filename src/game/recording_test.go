@@ -0,0 +1,69 @@
+package game
+
+import (
+	"slices"
+	"testing"
+
+	"mkoca/2048/src/replay"
+)
+
+// Game with two 2s in the top row and a random spawner that always picks the same cells
+func recordedGame(t *testing.T) *Game {
+	g := mustParseNotation(t, "11../..../..../....").NewGame()
+	g.spawner = NewSeededSpawner(1)
+	return g
+}
+
+func TestRecordGame(t *testing.T) {
+	g := recordedGame(t)
+	g.board.cells[2][2].isBlocked = true
+
+	testCases := []struct {
+		name     string
+		step     func()
+		expected []string
+	}{
+		{name: "new game", step: func() {}, expected: []string{""}},
+		{name: "moves", step: func() {
+			for _, d := range []Direction{RIGHT, DOWN, LEFT} {
+				PlayMove(g, d)
+				FinishAnimations(g)
+			}
+		}, expected: []string{"right", "down", "left", ""}},
+		{name: "undo", step: func() { Undo(g) }, expected: []string{"right", "down", ""}},
+		{name: "redo", step: func() { Redo(g) }, expected: []string{"right", "down", "left", ""}},
+		{name: "reset", step: func() { ResetGame(g) }, expected: []string{""}},
+	}
+
+	for _, tc := range testCases {
+		tc.step()
+		r := RecordGame(g)
+
+		actual := make([]string, r.Len())
+		for i := range actual {
+			actual[i] = r.Frame(i).Move
+		}
+		if !slices.Equal(actual, tc.expected) {
+			t.Errorf("%s: expected moves %q, found %q", tc.name, tc.expected, actual)
+		}
+
+		last := r.Frame(r.Len() - 1)
+		if last.Score != g.score {
+			t.Errorf("%s: expected the current score %d last, found %d", tc.name, g.score, last.Score)
+		}
+		if last.Board[2][2] != replay.BLOCKED_VALUE {
+			t.Errorf("%s: expected the blocked cell to be kept, found %d", tc.name, last.Board[2][2])
+		}
+	}
+}
+
+func TestRecordGameRightAfterMove(t *testing.T) {
+	g := mustParseNotation(t, "11../..../..../....").NewGame()
+	g.spawner = &ScriptedSpawner{spawns: []Spawn{{pos_x: 3, pos_y: 3, val: 4}}}
+	PlayMove(g, LEFT)
+
+	r := RecordGame(g)
+	if actual := r.Frame(r.Len() - 1).Board[3][3]; actual != 4 {
+		t.Errorf("expected the spawned 4 on the last board, found %d", actual)
+	}
+}
@@ -1,225 +1,193 @@
 package game
 
 import (
-	"errors"
+	"image"
 	"image/color"
 	"strconv"
 
+	"mkoca/2048/src/sound"
+
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
 )
 
-func (g *Game) Update() error {
-	if ebiten.IsKeyPressed(ebiten.KeyEscape) {
-		return errors.New("SIGKILL")
+// Queues the input, checks for the end of the game and plays the next queued move
+// Moves pressed during animations wait in the queue until the board is idle
+func updateRunning(g *Game) {
+	QueueInput(g)
+	if HasRunningAnimation(g) {
+		return
 	}
 
-	switch g.status {
-	case RUNNING:
-		if IsGameFinished(g.board.cells) {
-			g.status = FINISHED
-			break
-		}
+	if IsGameFinished(g.board.cells, g.target) {
+		g.status = FINISHED
+		g.input.Clear()
+		sound.Play(sound.WIN, 0)
+		return
+	}
 
-		if IsGameOver(g.board.cells) {
-			g.status = GAME_OVER
-			break
-		}
+	if IsGameOver(g.board.cells) {
+		g.status = GAME_OVER
+		g.input.Clear()
+		sound.Play(sound.GAME_OVER, 0)
+		return
+	}
 
-		// Only accept input if there are no animations running
-		if !HasRunningAnimation(g) {
-			dir, err := GetDirection()
-			if err == nil {
-				totalNumOfMovements, totalMergeScore := Move(g, dir)
+	PlayQueuedMove(g)
+}
 
-				if totalNumOfMovements > 0 {
-					emptyCell, err := GetRandomCell(g.board.cells)
+func drawBackground(g *Game, screen *ebiten.Image) {
+	theme := CurrentTheme()
+	fillRect(screen, g.board.bg.x, g.board.bg.y, g.board.bg.dx, g.board.bg.dy, theme.Radius(CELL_SIZE), theme.style.Board)
+}
 
-					if err == nil {
-						selectedCell := &g.board.cells[emptyCell.pos_x][emptyCell.pos_y]
-						selectedCell.isRendered = false
-						selectedCell.val = 0
-						selectedCell.animation = CreateCellAnimation(*selectedCell, CREATE_CELL_ANIMATION_DURATION)
-					}
+func drawBoard(g *Game, screen *ebiten.Image) {
+	theme := CurrentTheme()
 
-					g.score += totalMergeScore
-				}
+	// Cells first, tiles that are still animating are drawn on top of every cell afterwards
+	for _, row := range g.board.cells {
+		for _, cell := range row {
+			switch {
+			case cell.isBlocked:
+				fillRect(screen, cell.x, cell.y, CELL_SIZE, CELL_SIZE, theme.Radius(CELL_SIZE), theme.style.BlockedCell)
+			case cell.animation == nil && cell.isRendered:
+				drawTile(screen, g, cell.x, cell.y, CELL_SIZE, cell.val)
+			default:
+				fillRect(screen, cell.x, cell.y, CELL_SIZE, CELL_SIZE, theme.Radius(CELL_SIZE), theme.style.EmptyCell)
 			}
 		}
+	}
 
-	case FINISHED:
-		pressedKeys := inpututil.AppendJustPressedKeys(nil)
-		if len(pressedKeys) > 0 {
-			ResetGame(g)
-		}
-	case GAME_OVER:
-		pressedKeys := inpututil.AppendJustPressedKeys(nil)
-		if len(pressedKeys) > 0 {
-			ResetGame(g)
+	for _, row := range g.board.cells {
+		for _, cell := range row {
+			// Animations are stepped in Update, drawing only shows their current state
+			if cell.animation != nil {
+				cell.animation.Draw(screen, g)
+			}
 		}
-	default:
-		return errors.New("unhandled game status reached. exiting")
 	}
-
-	// TODO Add ctrl z to go back
-	return nil
 }
 
-func (g *Game) Draw(screen *ebiten.Image) {
-	switch g.status {
-	case RUNNING:
-		drawBackground(g, screen)
-		drawBoard(g, screen)
-		drawScoreboard(g, screen, g.board.bg.x+g.board.bg.dx, g.board.bg.y)
-	case FINISHED:
-		drawBackground(g, screen)
-		drawBoard(g, screen)
-		drawScoreboard(g, screen, g.board.bg.x+g.board.bg.dx, g.board.bg.y)
-		drawOverlay(g, screen)
-		drawAfterGameText(g, screen, "Congratulations!")
-	case GAME_OVER:
-		drawBackground(g, screen)
-		drawBoard(g, screen)
-		drawScoreboard(g, screen, g.board.bg.x+g.board.bg.dx, g.board.bg.y)
-		drawOverlay(g, screen)
-		drawAfterGameText(g, screen, "Game Over!")
-	default:
-		panic("TODO")
-	}
-}
+// Draws a tile of the given size with its value centered on it
+func drawTile(screen *ebiten.Image, g *Game, x int, y int, size int, val int) {
+	fillRect(screen, x, y, size, size, CurrentTheme().Radius(size), GetColor(val))
 
-func (g *Game) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
-	// s := ebiten.Monitor().DeviceScaleFactor()
-	return int(float64(outsideWidth) * 1), int(float64(outsideHeight))
+	txtOp := &text.DrawOptions{}
+	txtOp.ColorScale.ScaleWithColor(GetTextColor(val))
+	label := strconv.Itoa(val)
+	DrawCenteredText(screen, Fonts().TileFace(label, size), label, x+size/2, y+size/2, txtOp)
 }
 
-func drawBackground(g *Game, screen *ebiten.Image) {
-	bgImg := ebiten.NewImage(g.board.bg.dx, g.board.bg.dy)
-	bgImg.Fill(color.NRGBA{0x80, 0x80, 0x80, 0xff})
-	op := &ebiten.DrawImageOptions{}
-	op.GeoM.Translate(float64(g.board.bg.x), float64(g.board.bg.y))
+// Fills a rectangle with rounded corners, a radius of 0 gives sharp corners
+func fillRect(dst *ebiten.Image, x int, y int, w int, h int, radius float32, clr color.Color) {
+	x0, y0, x1, y1 := float32(x), float32(y), float32(x+w), float32(y+h)
+	radius = min(radius, float32(w)/2, float32(h)/2)
+	if radius <= 0 {
+		vector.DrawFilledRect(dst, x0, y0, float32(w), float32(h), clr, false)
+		return
+	}
 
-	screen.DrawImage(bgImg, op)
+	var path vector.Path
+	path.MoveTo(x0+radius, y0)
+	path.ArcTo(x1, y0, x1, y1, radius)
+	path.ArcTo(x1, y1, x0, y1, radius)
+	path.ArcTo(x0, y1, x0, y0, radius)
+	path.ArcTo(x0, y0, x1, y0, radius)
+	path.Close()
+
+	vs, is := path.AppendVerticesAndIndicesForFilling(nil, nil)
+	r, g, b, a := clr.RGBA()
+	for i := range vs {
+		vs[i].SrcX, vs[i].SrcY = 1, 1
+		vs[i].ColorR = float32(r) / 0xffff
+		vs[i].ColorG = float32(g) / 0xffff
+		vs[i].ColorB = float32(b) / 0xffff
+		vs[i].ColorA = float32(a) / 0xffff
+	}
+
+	op := &ebiten.DrawTrianglesOptions{}
+	op.ColorScaleMode = ebiten.ColorScaleModePremultipliedAlpha
+	op.AntiAlias = true
+	dst.DrawTriangles(vs, is, whitePixel(), op)
 }
 
-func drawBoard(g *Game, screen *ebiten.Image) {
-	cellImg := ebiten.NewImage(CELL_SIZE, CELL_SIZE)
-	cellImg.Fill(color.White)
+var whiteImage *ebiten.Image
 
-	textImg := ebiten.NewImage(CELL_SIZE, CELL_SIZE)
-	textImg.Fill(color.Black)
+// A white source pixel for DrawTriangles, created on first use
+func whitePixel() *ebiten.Image {
+	if whiteImage == nil {
+		whiteImage = ebiten.NewImage(3, 3)
+		whiteImage.Fill(color.White)
+	}
 
-	for _, row := range g.board.cells {
-		for _, cell := range row {
-			colour := GetColor(cell.val)
-			op := &ebiten.DrawImageOptions{}
-			op.ColorScale.ScaleWithColor(colour)
-			txtOp := &text.DrawOptions{}
-			op.GeoM.Translate(float64(cell.x), float64(cell.y))
+	return whiteImage.SubImage(image.Rect(1, 1, 2, 2)).(*ebiten.Image)
+}
 
-			screen.DrawImage(cellImg, op)
+func drawScoreboard(g *Game, screen *ebiten.Image, x int, y int) {
+	drawInfoBox(g, screen, x, y, "SCORE", strconv.Itoa(g.score))
+	drawScoreEffects(g, screen, x, y)
+}
 
-			if cell.animation != nil {
-				if cell.animation.GetStatus() == ANIM_FINISHED {
-					c := &g.board.cells[cell.pos_x][cell.pos_y]
-					// TODO
-					// Decide if animation should handle the logic on complete or renderer
-					// c.animation.OnFinish(cell.animation, g)
-					c.animation = nil
-					c.isRendered = true
-					c.val = 2
-				} else {
-					drawAnimation(screen, g, &cell)
-					animErr := cell.animation.Step()
-
-					if animErr != nil {
-						panic("step called on finished animation. check your logic")
-					}
-				}
-			} else if cell.isRendered {
-				txtOp.ColorScale.ScaleWithColor(color.Black)
-				DrawCenteredText(screen, g.fontFace, strconv.Itoa(cell.val), cell.x+CELL_SIZE/2, cell.y+CELL_SIZE/2, txtOp)
-			}
-		}
+// Draws the score effects above the info box drawn at x, y
+func drawScoreEffects(g *Game, screen *ebiten.Image, x int, y int) {
+	g.scorePos = Vec2{x: x + CELL_SIZE/2 + CELL_SIZE, y: y}
+	for _, effect := range g.effects {
+		effect.Draw(screen, g)
 	}
 }
 
-func drawScoreboard(g *Game, screen *ebiten.Image, x int, y int) {
+// Draws a labelled box to the right of x, same size as the scoreboard
+func drawInfoBox(g *Game, screen *ebiten.Image, x int, y int, label string, value string) {
 	x_offset := float64(x) + float64(CELL_SIZE)/2
 	y_offset := float64(y) + float64(GAP)
 	w := CELL_SIZE * 2
 	h := CELL_SIZE
-	scoreImg := ebiten.NewImage(w, h)
-	scoreImg.Fill(DARK_GRAY)
-	op := &ebiten.DrawImageOptions{}
-	op.GeoM.Translate(x_offset, y_offset)
-
-	screen.DrawImage(scoreImg, op)
+	theme := CurrentTheme()
+	fillRect(screen, int(x_offset), int(y_offset), w, h, theme.Radius(CELL_SIZE), theme.style.InfoBox)
 
 	txtOp := &text.DrawOptions{}
-	txtOp.ColorScale.ScaleWithColor(TEXT_DARK)
-	DrawCenteredText(screen, g.fontFace, "SCORE", int(x_offset)+w/2, int(y_offset)+h/4, txtOp)
+	txtOp.ColorScale.ScaleWithColor(theme.style.InfoLabel)
+	DrawCenteredText(screen, Fonts().Face(FONT_BODY), label, int(x_offset)+w/2, int(y_offset)+h/4, txtOp)
 	txtOp = &text.DrawOptions{}
-	DrawCenteredText(screen, g.fontFace, strconv.Itoa(g.score), int(x_offset)+w/2, int(y_offset)+3*h/4, txtOp)
+	DrawCenteredText(screen, Fonts().Face(FONT_BODY), value, int(x_offset)+w/2, int(y_offset)+3*h/4, txtOp)
 }
 
 func drawOverlay(g *Game, screen *ebiten.Image) {
-	overlayImage := ebiten.NewImage(g.board.bg.dx, g.board.bg.dy)
-	overlayImage.Fill(OVERLAY_BACKGROUND)
+	theme := CurrentTheme()
+	fillRect(screen, g.board.bg.x, g.board.bg.y, g.board.bg.dx, g.board.bg.dy, theme.Radius(CELL_SIZE), theme.overlay)
+}
 
-	overlayOpt := &ebiten.DrawImageOptions{}
-	overlayOpt.GeoM.Translate(float64(g.board.bg.x), float64(g.board.bg.y))
+func drawAfterGameText(g *Game, screen *ebiten.Image, message string, hint string) {
+	txtOp := &text.DrawOptions{}
+	txtOp.ColorScale.ScaleWithColor(CurrentTheme().text)
+	cx := g.board.bg.x + g.board.bg.dx/2
+	cy := g.board.bg.y + g.board.bg.dy/2
+	title := Fonts().Face(FONT_TITLE)
+	lh := title.Metrics().HAscent
+	DrawCenteredText(screen, title, message, cx, cy, txtOp)
 
-	screen.DrawImage(overlayImage, overlayOpt)
+	txtOp = &text.DrawOptions{}
+	txtOp.ColorScale.ScaleWithColor(CurrentTheme().mutedText)
+	DrawCenteredText(screen, Fonts().Face(FONT_CAPTION), hint, cx, cy+int(lh), txtOp)
 }
 
-func drawAnimation(screen *ebiten.Image, g *Game, cell *Cell) {
-
-	if cell.animation == nil {
-		panic("invalid state. drawAnimation should not be called when animation does not exists")
+// Text of a row in a list screen, the selected row is drawn on an info box
+func rowTextColor(selected bool) color.NRGBA {
+	if selected {
+		return ReadableTextColor(CurrentTheme().style.InfoBox)
 	}
 
-	switch cell.animation.GetType() {
-	case ANIM_CREATE_CELL:
-		ca := cell.animation.(*CreateAnimation)
-		c := ca.position
-
-		cellImg := ebiten.NewImage(ca.currentSize, ca.currentSize)
-		cellImg.Fill(GetColor(2))
-
-		cx := c.x + CELL_SIZE/2 // center x
-		cy := c.y + CELL_SIZE/2 // center y
-
-		cx = cx - ca.currentSize/2 // center x offsetted by current size
-		cy = cy - ca.currentSize/2 // center y offsetted by current size
-
-		op := &ebiten.DrawImageOptions{}
-		op.GeoM.Translate(float64(cx), float64(cy))
-		screen.DrawImage(cellImg, op)
-	default:
-		panic("unreachable")
-	}
+	return CurrentTheme().text
 }
 
-func drawAfterGameText(g *Game, screen *ebiten.Image, message string) {
+// Caption line centered below the board
+func drawFooterText(g *Game, screen *ebiten.Image, s string) {
 	txtOp := &text.DrawOptions{}
-	txtOp.ColorScale.ScaleWithColor(color.Black)
-	cx := g.board.bg.x + g.board.bg.dx/2
-	cy := g.board.bg.y + g.board.bg.dy/2
-	lh := g.fontFace.Metrics().HAscent
-	DrawCenteredText(screen, g.fontFace, message, cx, cy, txtOp)
-
-	txtOp = &text.DrawOptions{}
-	txtOp.ColorScale.ScaleWithColor(DARK_GRAY)
-	// TODO
-	// Ugly way to print text at different sizes
-	// Refactor this and accept size as parameter for DrawCenteredText
-	// Calculate predefined font size based on screen (like big, small, medium etc..)
-	tmp := g.fontFace.Size
-	g.fontFace.Size = tmp - 20
-	DrawCenteredText(screen, g.fontFace, "Press any key to reset", cx, cy+int(lh), txtOp)
-	g.fontFace.Size = tmp
+	txtOp.ColorScale.ScaleWithColor(CurrentTheme().mutedText)
+	cx, top := FooterPosition(g)
+	face := Fonts().Face(FONT_CAPTION)
+	DrawCenteredText(screen, face, s, cx, top+int(face.Size*1.5), txtOp)
 }
 
 func DrawCenteredText(screen *ebiten.Image, fontFace *text.GoTextFace, s string, cx int, cy int, txtOp *text.DrawOptions) {
@@ -0,0 +1,136 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"strings"
+
+	"mkoca/2048/src/sound"
+)
+
+// Name of the settings file under ConfigPath
+var SETTINGS_FILE = "settings.json"
+
+// Board sizes the settings accept
+var MIN_BOARD_SIZE = 3
+var MAX_BOARD_SIZE = 8
+
+// Animation speeds the settings accept, 2 plays animations twice as fast
+var MIN_ANIMATION_SPEED = 0.25
+var MAX_ANIMATION_SPEED = 4.0
+
+// What the player picked, kept as JSON under the user config directory
+// Fields missing from the file keep their defaults, keys only holds the actions bound differently from key_preset
+type Settings struct {
+	BoardSize      int                 `json:"board_size"`
+	FourChance     float64             `json:"four_chance"`
+	Theme          string              `json:"theme,omitempty"`
+	AnimationSpeed float64             `json:"animation_speed"`
+	ReducedMotion  bool                `json:"reduced_motion"`
+	SoundVolume    float64             `json:"sound_volume"`
+	Muted          bool                `json:"muted"`
+	KeyPreset      string              `json:"key_preset"`
+	Keys           map[string][]string `json:"keys,omitempty"`
+	UndoLimit      int                 `json:"undo_limit"`
+	FastForward    bool                `json:"fast_forward"`
+}
+
+func DefaultSettings() Settings {
+	return Settings{
+		BoardSize:      4,
+		AnimationSpeed: 1,
+		SoundVolume:    1,
+		KeyPreset:      KEY_PRESET_NAMES[0],
+		UndoLimit:      64,
+	}
+}
+
+// Reads the settings file, a missing file gives the defaults
+func LoadSettings() (Settings, error) {
+	s := DefaultSettings()
+
+	path, err := ConfigPath(SETTINGS_FILE)
+	if err != nil {
+		return s, err
+	}
+
+	if err := readJSON(path, &s); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return DefaultSettings(), err
+	}
+
+	return s, nil
+}
+
+func SaveSettings(s Settings) error {
+	path, err := ConfigPath(SETTINGS_FILE)
+	if err != nil {
+		return err
+	}
+
+	return writeJSON(path, s)
+}
+
+// Checks every value is in range, the theme is only checked when the settings are applied
+func (s Settings) Validate() error {
+	switch {
+	case s.BoardSize < MIN_BOARD_SIZE || s.BoardSize > MAX_BOARD_SIZE:
+		return fmt.Errorf("board_size must be between %d and %d, found %d", MIN_BOARD_SIZE, MAX_BOARD_SIZE, s.BoardSize)
+	case s.FourChance < 0 || s.FourChance > 1:
+		return fmt.Errorf("four_chance must be between 0 and 1, found %v", s.FourChance)
+	case s.AnimationSpeed < MIN_ANIMATION_SPEED || s.AnimationSpeed > MAX_ANIMATION_SPEED:
+		return fmt.Errorf("animation_speed must be between %v and %v, found %v", MIN_ANIMATION_SPEED, MAX_ANIMATION_SPEED, s.AnimationSpeed)
+	case s.SoundVolume < 0 || s.SoundVolume > 1:
+		return fmt.Errorf("sound_volume must be between 0 and 1, found %v", s.SoundVolume)
+	case s.UndoLimit < 0:
+		return fmt.Errorf("undo_limit must not be negative, found %d", s.UndoLimit)
+	}
+
+	return nil
+}
+
+// Makes the settings take effect for everything started from now on
+// Nothing changes when any of them is invalid
+func ApplySettings(s Settings) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+
+	kb, err := NewKeyBindings(s.KeyPreset, s.Keys)
+	if err != nil {
+		return err
+	}
+
+	// Selecting reads the theme files, so only do it when the theme changes
+	if s.Theme != "" && !strings.EqualFold(s.Theme, CurrentTheme().Name()) {
+		if err := SelectTheme(s.Theme); err != nil {
+			return err
+		}
+		Fonts().Reload()
+	}
+
+	CELL_COUNT = s.BoardSize
+	FOUR_CHANCE = s.FourChance
+	ANIMATION_SPEED = s.AnimationSpeed
+	REDUCED_MOTION = s.ReducedMotion
+	sound.SetVolume(s.SoundVolume)
+	sound.SetMuted(s.Muted)
+	MAX_UNDO = s.UndoLimit
+	FAST_FORWARD_INPUT = s.FastForward
+	SetBindings(kb)
+	return nil
+}
+
+// Mutes or unmutes the sound and keeps the choice in the settings file
+func ToggleMute() {
+	s, err := LoadSettings()
+	if err != nil {
+		log.Println("settings are not available:", err)
+	}
+
+	s.Muted = sound.ToggleMute()
+	if err := SaveSettings(s); err != nil {
+		log.Println("failed to save the settings:", err)
+	}
+}
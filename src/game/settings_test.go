@@ -0,0 +1,134 @@
+package game
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSettingsValidate(t *testing.T) {
+	testCases := []struct {
+		name          string
+		change        func(s *Settings)
+		expectedField string
+	}{
+		{name: "defaults", change: func(s *Settings) {}, expectedField: ""},
+		{name: "board too small", change: func(s *Settings) { s.BoardSize = 2 }, expectedField: "board_size"},
+		{name: "board too large", change: func(s *Settings) { s.BoardSize = 9 }, expectedField: "board_size"},
+		{name: "four chance", change: func(s *Settings) { s.FourChance = 1.5 }, expectedField: "four_chance"},
+		{name: "animation speed", change: func(s *Settings) { s.AnimationSpeed = 0 }, expectedField: "animation_speed"},
+		{name: "volume", change: func(s *Settings) { s.SoundVolume = -0.1 }, expectedField: "sound_volume"},
+		{name: "undo limit", change: func(s *Settings) { s.UndoLimit = -1 }, expectedField: "undo_limit"},
+		{name: "undo off", change: func(s *Settings) { s.UndoLimit = 0 }, expectedField: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := DefaultSettings()
+			tc.change(&s)
+			err := s.Validate()
+
+			if tc.expectedField == "" && err != nil {
+				t.Errorf("expected the settings to be valid, found %v", err)
+			}
+			if tc.expectedField != "" && (err == nil || !strings.Contains(err.Error(), tc.expectedField)) {
+				t.Errorf("expected an error about %s, found %v", tc.expectedField, err)
+			}
+		})
+	}
+}
+
+func TestSettingsMissingFields(t *testing.T) {
+	// Files written before a setting existed keep its default
+	s := DefaultSettings()
+	if err := json.Unmarshal([]byte(`{"key_preset": "wasd", "board_size": 5}`), &s); err != nil {
+		t.Fatal(err)
+	}
+
+	if s.KeyPreset != "wasd" || s.BoardSize != 5 || s.AnimationSpeed != 1 || s.UndoLimit != 64 {
+		t.Errorf("expected the missing fields to keep their defaults, found %+v", s)
+	}
+}
+
+func TestApplySettings(t *testing.T) {
+	t.Cleanup(func() { ApplySettings(DefaultSettings()) })
+
+	s := DefaultSettings()
+	s.BoardSize, s.UndoLimit, s.ReducedMotion = 5, 3, true
+	if err := ApplySettings(s); err != nil {
+		t.Fatal(err)
+	}
+
+	if CELL_COUNT != 5 || MAX_UNDO != 3 || !REDUCED_MOTION {
+		t.Errorf("expected the settings to take effect, found %d %d %v", CELL_COUNT, MAX_UNDO, REDUCED_MOTION)
+	}
+
+	s.BoardSize, s.KeyPreset = 6, "dvorak"
+	if err := ApplySettings(s); err == nil {
+		t.Error("expected an unknown key preset to be rejected")
+	}
+
+	if CELL_COUNT != 5 {
+		t.Errorf("expected invalid settings to change nothing, found a board size of %d", CELL_COUNT)
+	}
+}
+
+func TestFourChance(t *testing.T) {
+	testCases := []struct {
+		name     string
+		chance   float64
+		expected int
+	}{
+		{name: "never", chance: 0, expected: 2},
+		{name: "always", chance: 1, expected: 4},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			spawner := NewSeededSpawner(1)
+			spawner.fourChance = tc.chance
+
+			s, err := spawner.NextSpawn(NewBoardAt(4, 0, 0).cells)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if s.val != tc.expected {
+				t.Errorf("expected a chance of %v to spawn a %d, found %d", tc.chance, tc.expected, s.val)
+			}
+		})
+	}
+}
+
+func TestGameKeysFollowBindings(t *testing.T) {
+	t.Cleanup(func() { SetBindings(ARROW_KEYS) })
+
+	g := InitGame()
+	SetBindings(WASD_KEYS)
+	if !slices.Equal(GameKeys(g)[ACTION_UP], WASD_KEYS[ACTION_UP]) {
+		t.Errorf("expected a running game to use the new bindings, found %v", GameKeys(g)[ACTION_UP])
+	}
+
+	g.keys = ARROW_KEYS
+	if !slices.Equal(GameKeys(g)[ACTION_UP], ARROW_KEYS[ACTION_UP]) {
+		t.Errorf("expected a game with its own keys to keep them, found %v", GameKeys(g)[ACTION_UP])
+	}
+}
+
+func TestRestartAppliesBoardSize(t *testing.T) {
+	t.Cleanup(func() { ApplySettings(DefaultSettings()) })
+
+	cg := NewClassicGame(NewApp(), InitGame())
+	s := DefaultSettings()
+	s.BoardSize, s.FourChance = 5, 1
+	if err := ApplySettings(s); err != nil {
+		t.Fatal(err)
+	}
+
+	cg.restart()
+	cells := cg.game.board.cells
+	if len(cells) != 5 || MaxTile(cells) != 4 {
+		t.Errorf("expected a 5x5 board starting with a 4, found %d cells per side and %d", len(cells), MaxTile(cells))
+	}
+}
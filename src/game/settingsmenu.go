@@ -0,0 +1,155 @@
+package game
+
+import (
+	"fmt"
+	"image"
+	"log"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Widget rows of the settings screen
+const SETTINGS_ROWS = 12
+
+// Edits the settings file, changes are applied right away and saved when the screen closes
+// The board size and the chance of 4s apply from the next game on, a paused game picks them up once it is restarted
+type SettingsMenu struct {
+	app      *App
+	ui       *UI
+	settings Settings
+	// The key binding screen saves the keys itself, read them back once it closes
+	reload bool
+}
+
+func NewSettingsMenu(app *App) *SettingsMenu {
+	sm := &SettingsMenu{app: app, ui: NewUI()}
+	sm.load()
+	return sm
+}
+
+func (sm *SettingsMenu) load() {
+	s, err := LoadSettings()
+	if err != nil {
+		log.Println("settings are not available:", err)
+	}
+
+	sm.settings = s
+}
+
+// Rows as wide as two thirds of the screen
+func settingsArea() image.Rectangle {
+	w, h := ScreenSize()
+	height := UIHeight(SETTINGS_ROWS)
+	top := (h - height) / 2
+	return image.Rect(w/6, top, w*5/6, top+height)
+}
+
+func (sm *SettingsMenu) Update() error {
+	if sm.reload {
+		sm.reload = false
+		sm.load()
+	}
+
+	app, ui, s := sm.app, sm.ui, &sm.settings
+	changed := false
+
+	ui.Begin(settingsArea())
+
+	size := float64(s.BoardSize)
+	if ui.Slider(fmt.Sprintf("Board %dx%d", s.BoardSize, s.BoardSize), &size, float64(MIN_BOARD_SIZE), float64(MAX_BOARD_SIZE), 1) {
+		s.BoardSize = int(size)
+		changed = true
+	}
+
+	changed = ui.Slider(fmt.Sprintf("Chance of a 4: %.0f%%", s.FourChance*100), &s.FourChance, 0, 1, 0.05) || changed
+
+	themes := BundledThemes()
+	names := make([]string, len(themes))
+	selected := 0
+	for i, t := range themes {
+		names[i] = t.Name()
+		if t.Name() == CurrentTheme().Name() {
+			selected = i
+		}
+	}
+	if ui.Dropdown("Theme", names, &selected) {
+		s.Theme = names[selected]
+		changed = true
+	}
+
+	changed = ui.Slider(fmt.Sprintf("Animation speed: %gx", s.AnimationSpeed), &s.AnimationSpeed, MIN_ANIMATION_SPEED, MAX_ANIMATION_SPEED, 0.25) || changed
+	changed = ui.Toggle("Reduced motion", &s.ReducedMotion) || changed
+	soundOn := !s.Muted
+	if ui.Toggle("Sound", &soundOn) {
+		s.Muted = !soundOn
+		changed = true
+	}
+	changed = ui.Slider(fmt.Sprintf("Sound volume: %.0f%%", s.SoundVolume*100), &s.SoundVolume, 0, 1, 0.1) || changed
+
+	preset := max(slices.Index(KEY_PRESET_NAMES, s.KeyPreset), 0)
+	if ui.Dropdown("Keys", KEY_PRESET_NAMES, &preset) {
+		// Single keys are bound relative to the preset, they do not carry over
+		s.KeyPreset, s.Keys = KEY_PRESET_NAMES[preset], nil
+		changed = true
+	}
+
+	undo := float64(s.UndoLimit)
+	label := fmt.Sprintf("Undo limit: %d", s.UndoLimit)
+	if s.UndoLimit == 0 {
+		label = "Undo limit: off"
+	}
+	if ui.Slider(label, &undo, 0, 128, 1) {
+		s.UndoLimit = int(undo)
+		changed = true
+	}
+
+	changed = ui.Toggle("Fast forward", &s.FastForward) || changed
+
+	if ui.Button("Key bindings") {
+		sm.save()
+		sm.reload = true
+		app.Push(NewKeyBindingScreen(app))
+	}
+	back := ui.Button("Back")
+	ui.End()
+
+	if changed {
+		if err := ApplySettings(*s); err != nil {
+			log.Println("invalid settings:", err)
+		}
+	}
+
+	if back || ui.Back() {
+		sm.save()
+		app.Pop()
+	}
+
+	return nil
+}
+
+func (sm *SettingsMenu) save() {
+	if err := SaveSettings(sm.settings); err != nil {
+		log.Println("failed to save the settings:", err)
+	}
+}
+
+func (sm *SettingsMenu) Draw(screen *ebiten.Image) {
+	screen.Fill(CurrentTheme().style.Board)
+	drawMenuTitle(screen, "Settings", settingsArea().Min.Y)
+	sm.ui.Draw(screen)
+}
+
+func (sm *SettingsMenu) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
+	return ScaledSize(outsideWidth, outsideHeight)
+}
+
+// Shrinks the cells until every row and the title fit on the screen
+func (sm *SettingsMenu) Resize(width int, height int) {
+	area := image.Rect(0, 0, width, height)
+	SetCellSize(FitCellSize(LayoutSpec{boxes: 1}, CELL_COUNT, area, OrientationOf(area)))
+
+	for CELL_SIZE > MIN_CELL_SIZE && UIHeight(SETTINGS_ROWS+2) > height {
+		SetCellSize(CELL_SIZE - 1)
+	}
+}
@@ -0,0 +1,177 @@
+package game
+
+import (
+	"errors"
+	"math/bits"
+	"math/rand/v2"
+	"time"
+
+	"mkoca/2048/src/sound"
+)
+
+// A single tile placement on the board
+// pos_x is the row index and pos_y is the column index, same as Cell
+type Spawn struct {
+	pos_x int
+	pos_y int
+	val   int
+}
+
+// Spawner decides where the next tile appears and what its value is
+type Spawner interface {
+	NextSpawn(cells [][]Cell) (Spawn, error)
+}
+
+// Chance of a tile spawned by InitGame being a 4 instead of a 2
+var FOUR_CHANCE = 0.0
+
+// Picks a random empty cell and spawns a 2, or a 4 with a chance of fourChance
+// A nil rng falls back to the global source
+type RandomSpawner struct {
+	rng        *rand.Rand
+	fourChance float64
+}
+
+// Replays a fixed list of spawns in order
+// Once the list is exhausted the fallback takes over, without one no more tiles are spawned
+type ScriptedSpawner struct {
+	spawns   []Spawn
+	index    int
+	fallback Spawner
+}
+
+// Spawns whatever the spawning player picked, each pick is used once
+type ManualSpawner struct {
+	pending *Spawn
+}
+
+// Random spawner with its own source, two spawners with the same seed spawn identically
+func NewSeededSpawner(seed uint64) *RandomSpawner {
+	return &RandomSpawner{rng: rand.New(rand.NewPCG(seed, bits.ReverseBytes64(seed)))}
+}
+
+// impl RandomSpawner
+
+func (spawner *RandomSpawner) NextSpawn(cells [][]Cell) (Spawn, error) {
+	intN, float := rand.IntN, rand.Float64
+	if spawner.rng != nil {
+		intN, float = spawner.rng.IntN, spawner.rng.Float64
+	}
+
+	cell, err := pickRandomCell(cells, intN)
+	if err != nil {
+		return Spawn{}, err
+	}
+
+	// Without a chance of 4s no extra number is drawn, so seeded sequences stay the same
+	val := 2
+	if spawner.fourChance > 0 && float() < spawner.fourChance {
+		val = 4
+	}
+
+	return Spawn{pos_x: cell.pos_x, pos_y: cell.pos_y, val: val}, nil
+}
+
+// end
+
+// impl ScriptedSpawner
+
+func (spawner *ScriptedSpawner) NextSpawn(cells [][]Cell) (Spawn, error) {
+	if spawner.index >= len(spawner.spawns) {
+		if spawner.fallback != nil {
+			return spawner.fallback.NextSpawn(cells)
+		}
+
+		return Spawn{}, errors.New("spawn script is exhausted")
+	}
+
+	s := spawner.spawns[spawner.index]
+	spawner.index++
+
+	if s.pos_x < 0 || s.pos_x >= len(cells) || s.pos_y < 0 || s.pos_y >= len(cells[s.pos_x]) {
+		return Spawn{}, errors.New("scripted spawn is outside of the board")
+	}
+
+	if cells[s.pos_x][s.pos_y].isRendered || cells[s.pos_x][s.pos_y].isBlocked {
+		return Spawn{}, errors.New("scripted spawn cell is occupied")
+	}
+
+	return s, nil
+}
+
+// end
+
+// impl ManualSpawner
+
+func (spawner *ManualSpawner) Choose(s Spawn) {
+	spawner.pending = &s
+}
+
+func (spawner *ManualSpawner) NextSpawn(cells [][]Cell) (Spawn, error) {
+	if spawner.pending == nil {
+		return Spawn{}, errors.New("no spawn chosen")
+	}
+
+	s := *spawner.pending
+	spawner.pending = nil
+
+	if s.pos_x < 0 || s.pos_x >= len(cells) || s.pos_y < 0 || s.pos_y >= len(cells[s.pos_x]) {
+		return Spawn{}, errors.New("chosen spawn is outside of the board")
+	}
+
+	if cells[s.pos_x][s.pos_y].isRendered || cells[s.pos_x][s.pos_y].isBlocked {
+		return Spawn{}, errors.New("chosen spawn cell is occupied")
+	}
+
+	return s, nil
+}
+
+// end
+
+// Places the next spawn directly on the board without an animation
+func PlaceSpawn(g *Game) error {
+	s, err := g.spawner.NextSpawn(g.board.cells)
+	if err != nil {
+		return err
+	}
+
+	c := &g.board.cells[s.pos_x][s.pos_y]
+	c.isRendered = true
+	c.val = s.val
+	return nil
+}
+
+// Starts a create animation on the next spawn cell
+// The cell becomes part of the board once the animation finishes
+func SpawnCell(g *Game) error {
+	return SpawnCellAfter(g, 0)
+}
+
+// Same as SpawnCell but the tile only starts growing after the delay, e.g. once the other tiles slid into place
+func SpawnCellAfter(g *Game, delay time.Duration) error {
+	s, err := g.spawner.NextSpawn(g.board.cells)
+	if err != nil {
+		return err
+	}
+
+	c := &g.board.cells[s.pos_x][s.pos_y]
+	c.isRendered = false
+	c.val = 0
+
+	var anim Animation = CreateCellAnimation(*c, s.val, CREATE_CELL_ANIMATION_DURATION)
+	if delay > 0 {
+		// The tile appears once the delay is over
+		wait := Delay(delay)
+		wait.OnComplete(func() { sound.Play(sound.SPAWN, 0) })
+		anim = Sequence(wait, anim)
+	} else {
+		sound.Play(sound.SPAWN, 0)
+	}
+	anim.OnComplete(func() {
+		c.isRendered = true
+		c.val = s.val
+	})
+
+	c.animation = anim
+	return nil
+}
@@ -0,0 +1,144 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+// Name of the classic mode stats under ConfigPath
+var STATS_FILE = "stats.json"
+
+// Totals over every finished classic game
+type Stats struct {
+	Games      int `json:"games"`
+	Wins       int `json:"wins"`
+	BestScore  int `json:"best_score"`
+	BestTile   int `json:"best_tile"`
+	TotalMoves int `json:"total_moves"`
+}
+
+// Shows the classic stats and the daily challenge results
+type StatsScreen struct {
+	app   *App
+	lines []string
+}
+
+// Reads the stats file, a missing file gives empty stats
+func LoadStats() (Stats, error) {
+	var s Stats
+
+	path, err := ConfigPath(STATS_FILE)
+	if err != nil {
+		return s, err
+	}
+
+	if err := readJSON(path, &s); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return Stats{}, err
+	}
+
+	return s, nil
+}
+
+// impl Stats
+
+// Adds a game that just ended
+func (s *Stats) Record(g *Game) {
+	s.Games++
+	if g.status == FINISHED {
+		s.Wins++
+	}
+
+	s.BestScore = max(s.BestScore, g.score)
+	s.BestTile = max(s.BestTile, MaxTile(g.board.cells))
+	s.TotalMoves += g.moves
+}
+
+func (s *Stats) Save() error {
+	path, err := ConfigPath(STATS_FILE)
+	if err != nil {
+		return err
+	}
+
+	return writeJSON(path, s)
+}
+
+// end
+
+// impl StatsScreen
+
+func NewStatsScreen(app *App) *StatsScreen {
+	ss := StatsScreen{app: app}
+
+	stats, err := LoadStats()
+	if err != nil {
+		ss.lines = append(ss.lines, "Stats are not available: "+err.Error())
+	}
+
+	ss.lines = append(ss.lines,
+		fmt.Sprintf("Games played: %d", stats.Games),
+		fmt.Sprintf("Games won: %d", stats.Wins),
+		fmt.Sprintf("Best score: %d", stats.BestScore),
+		fmt.Sprintf("Best tile: %d", stats.BestTile),
+		fmt.Sprintf("Moves played: %d", stats.TotalMoves),
+		"",
+	)
+
+	history, err := LoadDailyHistory()
+	if err != nil {
+		ss.lines = append(ss.lines, "Daily results are not available: "+err.Error())
+		return &ss
+	}
+
+	completed, best := 0, 0
+	for _, r := range history.Results {
+		if r.Status == DAILY_FINISHED {
+			completed++
+		}
+		best = max(best, r.Score)
+	}
+
+	ss.lines = append(ss.lines,
+		fmt.Sprintf("Daily challenges played: %d", len(history.Results)),
+		fmt.Sprintf("Daily challenges completed: %d", completed),
+		fmt.Sprintf("Best daily score: %d", best),
+	)
+
+	return &ss
+}
+
+func (ss *StatsScreen) Update() error {
+	if Bindings().JustPressed(ACTION_QUIT) || AnyPointerJustPressed() {
+		ss.app.Pop()
+	}
+
+	return nil
+}
+
+func (ss *StatsScreen) Draw(screen *ebiten.Image) {
+	w, h := screen.Bounds().Dx(), screen.Bounds().Dy()
+	screen.Fill(CurrentTheme().style.Board)
+
+	face := Fonts().Face(FONT_BODY)
+	lh := int(face.Size * 1.5)
+	top := (h - lh*(len(ss.lines)+2)) / 2
+
+	txtOp := &text.DrawOptions{}
+	txtOp.ColorScale.ScaleWithColor(CurrentTheme().text)
+	DrawCenteredText(screen, Fonts().Face(FONT_TITLE), "Stats", w/2, top, txtOp)
+
+	for i, line := range ss.lines {
+		txtOp = &text.DrawOptions{}
+		txtOp.ColorScale.ScaleWithColor(CurrentTheme().text)
+		DrawCenteredText(screen, face, line, w/2, top+lh*(i+2), txtOp)
+	}
+}
+
+func (ss *StatsScreen) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
+	return ScaledSize(outsideWidth, outsideHeight)
+}
+
+// end
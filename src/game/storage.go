@@ -0,0 +1,58 @@
+package game
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"mkoca/2048/src/config"
+)
+
+// Path of a file in the config directory, see config.DIR_NAME
+func ConfigPath(name string) (string, error) {
+	return config.Path(name)
+}
+
+// Directory of bundled assets like PUZZLE_DIR, relative paths are tried in the working directory first
+// and then next to the binary, so puzzles load and save in the same place wherever the game is started from
+func AssetDir(dir string) string {
+	if filepath.IsAbs(dir) {
+		return dir
+	}
+
+	if _, err := os.Stat(dir); err == nil {
+		return dir
+	}
+
+	if exe, err := os.Executable(); err == nil {
+		beside := filepath.Join(filepath.Dir(exe), dir)
+		if _, err := os.Stat(beside); err == nil {
+			return beside
+		}
+	}
+
+	return dir
+}
+
+func readJSON(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+// Writes v as indented JSON, creating parent directories as needed
+func writeJSON(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o644)
+}
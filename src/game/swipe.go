@@ -0,0 +1,159 @@
+package game
+
+import (
+	"image"
+	"math"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// Shortest drag in cells that counts as a swipe, follows the cell size so it works the same at every scale
+var SWIPE_MIN_DISTANCE = 0.4
+
+// Largest angle in degrees between a swipe and the nearest axis, more diagonal drags are ignored
+var SWIPE_ANGLE_TOLERANCE = 30.0
+
+// A mouse drag or touch that is still held down
+// Every gesture moves the board at most once, the pointer has to be lifted for the next one
+type swipe struct {
+	start    Vec2
+	resolved bool
+}
+
+// Turns mouse drags and touches into directions
+type SwipeDetector struct {
+	mouse   *swipe
+	touches map[ebiten.TouchID]*swipe
+}
+
+func NewSwipeDetector() *SwipeDetector {
+	return &SwipeDetector{touches: map[ebiten.TouchID]*swipe{}}
+}
+
+// Direction of a drag by dx, dy pixels
+// Returns false for drags shorter than minDistance or further than tolerance degrees off an axis
+func ResolveSwipe(dx int, dy int, minDistance float64, tolerance float64) (Direction, bool) {
+	fx, fy := float64(dx), float64(dy)
+	if math.Hypot(fx, fy) < minDistance {
+		return UP, false
+	}
+
+	major, minor := math.Abs(fx), math.Abs(fy)
+	if minor > major {
+		major, minor = minor, major
+	}
+
+	if math.Atan2(minor, major)*180/math.Pi > tolerance {
+		return UP, false
+	}
+
+	// Screen coordinates grow downwards
+	if math.Abs(fx) > math.Abs(fy) {
+		if fx > 0 {
+			return RIGHT, true
+		}
+		return LEFT, true
+	}
+
+	if fy > 0 {
+		return DOWN, true
+	}
+	return UP, true
+}
+
+// True on the tick the left mouse button or any finger goes down
+func AnyPointerJustPressed() bool {
+	return inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) || len(inpututil.AppendJustPressedTouchIDs(nil)) > 0
+}
+
+// Keyboard direction of the game, or else one of its gamepad or a swipe on its area
+func GetGameDirection(g *Game) (Direction, error) {
+	dir, err := GetDirectionFor(GameKeys(g))
+	if err == nil {
+		return dir, nil
+	}
+
+	if d, ok := g.gamepad.Direction(time.Now()); ok {
+		return d, nil
+	}
+
+	if g.swipes == nil {
+		return dir, err
+	}
+
+	if d, ok := g.swipes.Poll(g.layout.area); ok {
+		return d, nil
+	}
+
+	return dir, err
+}
+
+// impl SwipeDetector
+
+// Follows the mouse and every touch, returns the direction of the first gesture that became a swipe
+// Only gestures starting inside area count, an empty area accepts the whole screen
+func (sd *SwipeDetector) Poll(area image.Rectangle) (Direction, bool) {
+	minDistance := SWIPE_MIN_DISTANCE * float64(CELL_SIZE)
+
+	if ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
+		x, y := ebiten.CursorPosition()
+		if d, ok := sd.track(&sd.mouse, area, x, y, minDistance); ok {
+			return d, true
+		}
+	} else {
+		sd.mouse = nil
+	}
+
+	ids := ebiten.AppendTouchIDs(nil)
+
+	// Forget lifted fingers
+	held := make(map[ebiten.TouchID]bool, len(ids))
+	for _, id := range ids {
+		held[id] = true
+	}
+	for id := range sd.touches {
+		if !held[id] {
+			delete(sd.touches, id)
+		}
+	}
+
+	for _, id := range ids {
+		s := sd.touches[id]
+		x, y := ebiten.TouchPosition(id)
+		d, ok := sd.track(&s, area, x, y, minDistance)
+		if s != nil {
+			sd.touches[id] = s
+		}
+
+		if ok {
+			return d, true
+		}
+	}
+
+	return UP, false
+}
+
+// Starts a gesture at x, y or checks whether the held one has become a swipe
+func (sd *SwipeDetector) track(s **swipe, area image.Rectangle, x int, y int, minDistance float64) (Direction, bool) {
+	if *s == nil {
+		if area.Empty() || image.Pt(x, y).In(area) {
+			*s = &swipe{start: Vec2{x: x, y: y}}
+		}
+		return UP, false
+	}
+
+	if (*s).resolved {
+		return UP, false
+	}
+
+	d, ok := ResolveSwipe(x-(*s).start.x, y-(*s).start.y, minDistance, SWIPE_ANGLE_TOLERANCE)
+	if ok {
+		(*s).resolved = true
+	}
+
+	return d, ok
+}
+
+// end
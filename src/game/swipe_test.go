@@ -0,0 +1,34 @@
+package game
+
+import "testing"
+
+func TestResolveSwipe(t *testing.T) {
+	testCases := []struct {
+		name     string
+		dx, dy   int
+		expected Direction
+		ok       bool
+	}{
+		{name: "right", dx: 50, dy: 0, expected: RIGHT, ok: true},
+		{name: "left", dx: -50, dy: 5, expected: LEFT, ok: true},
+		{name: "up", dx: 0, dy: -50, expected: UP, ok: true},
+		{name: "down", dx: -10, dy: 50, expected: DOWN, ok: true},
+		{name: "too short", dx: 10, dy: 0, ok: false},
+		{name: "diagonal", dx: 40, dy: 40, ok: false},
+		{name: "just inside tolerance", dx: 50, dy: 28, expected: RIGHT, ok: true},
+		{name: "just outside tolerance", dx: 50, dy: 30, ok: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			d, ok := ResolveSwipe(tc.dx, tc.dy, 20, 30)
+			if ok != tc.ok {
+				t.Fatalf("expected ok to be %t, found %t", tc.ok, ok)
+			}
+
+			if ok && d != tc.expected {
+				t.Errorf("expected %d, found %d", tc.expected, d)
+			}
+		})
+	}
+}
@@ -0,0 +1,245 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"mkoca/2048/src/replay"
+)
+
+const THEME_DIR = "assets/themes"
+
+// Everything that decides how the board looks
+// The board, cells, info boxes and tiles are the style board images are drawn in
+type Theme struct {
+	name      string
+	style     replay.Style
+	overlay   color.NRGBA
+	text      color.NRGBA
+	mutedText color.NRGBA
+	font      string
+}
+
+// The style fields of replay.StyleFile and the colours only the game draws
+// Every field is optional, missing ones keep the classic value
+// A missing or "auto" tile text colour is picked by contrast, info_text and highlight follow info_box and the 2048 tile
+type themeFile struct {
+	replay.StyleFile
+	Name      string `json:"name"`
+	Overlay   string `json:"overlay"`
+	Text      string `json:"text"`
+	MutedText string `json:"muted_text"`
+	Font      string `json:"font"`
+}
+
+var currentTheme = ClassicTheme()
+var bundledThemes []*Theme
+
+// The built in look, also used whenever the theme files can not be read
+func ClassicTheme() *Theme {
+	tiles := make(map[int]replay.TileStyle, len(TileColors))
+	for val, fill := range TileColors {
+		tiles[val] = replay.TileStyle{Fill: fill, Text: ReadableTextColor(fill)}
+	}
+
+	return &Theme{
+		name: "Classic",
+		style: replay.Style{
+			Board:       BOARD_GRAY,
+			EmptyCell:   DARK_GRAY,
+			BlockedCell: BLOCKED_CELL,
+			InfoBox:     DARK_GRAY,
+			InfoLabel:   TEXT_DARK,
+			InfoText:    ReadableTextColor(DARK_GRAY),
+			Highlight:   TileColors[2048],
+			Tiles:       tiles,
+			Fallback:    replay.TileStyle{Fill: DARK_GRAY, Text: ReadableTextColor(DARK_GRAY)},
+		},
+		overlay:   OVERLAY_BACKGROUND,
+		text:      color.NRGBA{0x00, 0x00, 0x00, 0xff},
+		mutedText: DARK_GRAY,
+	}
+}
+
+func CurrentTheme() *Theme {
+	return currentTheme
+}
+
+// Switches the theme for everything drawn from now on
+func SetTheme(t *Theme) {
+	currentTheme = t
+}
+
+// Themes in THEME_DIR, loaded on first use
+// Empty when they could not be loaded
+func BundledThemes() []*Theme {
+	if bundledThemes == nil {
+		themes, err := LoadThemes(THEME_DIR)
+		if err != nil {
+			return nil
+		}
+		bundledThemes = themes
+	}
+
+	return bundledThemes
+}
+
+// Switches to the bundled theme after the current one
+// Returns false when no bundled themes could be loaded
+func CycleTheme() bool {
+	if len(BundledThemes()) == 0 {
+		return false
+	}
+
+	next := 0
+	for i, t := range bundledThemes {
+		if t.name == currentTheme.name {
+			next = (i + 1) % len(bundledThemes)
+		}
+	}
+
+	SetTheme(bundledThemes[next])
+	return true
+}
+
+// Switches to a bundled theme by name, e.g. "dark", or to a theme file by path
+func SelectTheme(nameOrPath string) error {
+	if strings.HasSuffix(nameOrPath, ".json") {
+		t, err := LoadTheme(nameOrPath)
+		if err != nil {
+			return err
+		}
+
+		SetTheme(t)
+		return nil
+	}
+
+	themes, err := LoadThemes(THEME_DIR)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range themes {
+		if strings.EqualFold(strings.ReplaceAll(t.name, " ", "-"), strings.ReplaceAll(nameOrPath, " ", "-")) {
+			bundledThemes = themes
+			SetTheme(t)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("theme %q not found in %s", nameOrPath, THEME_DIR)
+}
+
+func ParseTheme(data []byte) (*Theme, error) {
+	var file themeFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+
+	t := ClassicTheme()
+	if file.Name != "" {
+		t.name = file.Name
+	}
+
+	style, err := replay.ParseStyle(file.StyleFile, t.style, ReadableTextColor)
+	if err != nil {
+		return nil, err
+	}
+	t.style = style
+
+	if file.InfoText == "" {
+		t.style.InfoText = ReadableTextColor(t.style.InfoBox)
+	}
+	if file.Highlight == "" {
+		t.style.Highlight = t.Tile(2048).Fill
+	}
+
+	fields := []struct {
+		name  string
+		value string
+		dst   *color.NRGBA
+	}{
+		{"overlay", file.Overlay, &t.overlay},
+		{"text", file.Text, &t.text},
+		{"muted_text", file.MutedText, &t.mutedText},
+	}
+
+	for _, f := range fields {
+		if f.value == "" {
+			continue
+		}
+
+		c, err := replay.ParseHexColor(f.value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.name, err)
+		}
+		*f.dst = c
+	}
+
+	t.font = file.Font
+
+	return t, nil
+}
+
+func LoadTheme(path string) (*Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := ParseTheme(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return t, nil
+}
+
+// Loads every theme in dir sorted by file name
+func LoadThemes(dir string) ([]*Theme, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(paths)
+	themes := make([]*Theme, 0, len(paths))
+
+	for _, path := range paths {
+		t, err := LoadTheme(path)
+		if err != nil {
+			return nil, err
+		}
+
+		themes = append(themes, t)
+	}
+
+	return themes, nil
+}
+
+// impl Theme
+
+func (t *Theme) Name() string {
+	return t.name
+}
+
+// Style of a tile value, 0 is an empty cell
+func (t *Theme) Tile(val int) replay.TileStyle {
+	if val == 0 {
+		return replay.TileStyle{Fill: t.style.EmptyCell, Text: t.text}
+	}
+
+	return t.style.Tile(val)
+}
+
+// Corner radius in pixels for a rectangle of the given size
+func (t *Theme) Radius(size int) float32 {
+	return float32(t.style.Radius(size))
+}
+
+// end
@@ -0,0 +1,182 @@
+package game
+
+import (
+	"image/color"
+	"path/filepath"
+	"testing"
+)
+
+type ThemeParseTest struct {
+	name        string
+	input       string
+	expectedErr bool
+}
+
+func TestParseTheme(t *testing.T) {
+	testCases := []ThemeParseTest{
+		{name: "empty keeps classic", input: `{}`, expectedErr: false},
+		{name: "full colours", input: `{"board": "#112233", "overlay": "#11223344", "tiles": {"2": {"fill": "#ffffff", "text": "#000000"}}}`, expectedErr: false},
+		{name: "invalid json", input: `{"board": }`, expectedErr: true},
+		{name: "missing hash", input: `{"board": "112233"}`, expectedErr: true},
+		{name: "short colour", input: `{"board": "#123"}`, expectedErr: true},
+		{name: "not hex", input: `{"board": "#gg2233"}`, expectedErr: true},
+		{name: "invalid tile value", input: `{"tiles": {"3": {"fill": "#ffffff", "text": "#000000"}}}`, expectedErr: true},
+		{name: "missing tile text", input: `{"tiles": {"2": {"fill": "#ffffff"}}}`, expectedErr: false},
+		{name: "auto tile text", input: `{"tiles": {"2": {"fill": "#ffffff", "text": "auto"}}}`, expectedErr: false},
+		{name: "invalid tile text", input: `{"tiles": {"2": {"fill": "#ffffff", "text": "black"}}}`, expectedErr: true},
+		{name: "negative radius", input: `{"corner_radius": -1}`, expectedErr: true},
+		{name: "radius in pixels", input: `{"corner_radius": 8}`, expectedErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseTheme([]byte(tc.input))
+			if tc.expectedErr && err == nil {
+				t.Errorf("expected an error")
+			} else if !tc.expectedErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestThemeFallback(t *testing.T) {
+	explicit, err := ParseTheme([]byte(`{"tiles": {"2": {"fill": "#010101", "text": "#000000"}}, "fallback": {"fill": "#020202", "text": "#ffffff"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if fill := explicit.Tile(4096).Fill; fill != (color.NRGBA{2, 2, 2, 0xff}) {
+		t.Errorf("expected the explicit fallback beyond the table, found %v", fill)
+	}
+
+	implicit, err := ParseTheme([]byte(`{"tiles": {"2": {"fill": "#010101", "text": "#000000"}, "8": {"fill": "#080808", "text": "#000000"}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if fill := implicit.Tile(4096).Fill; fill != (color.NRGBA{8, 8, 8, 0xff}) {
+		t.Errorf("expected the largest tile style beyond the table, found %v", fill)
+	}
+
+	if fill := implicit.Tile(0).Fill; fill != implicit.style.EmptyCell {
+		t.Errorf("expected empty cells to use the empty cell colour, found %v", fill)
+	}
+}
+
+func TestThemeRadius(t *testing.T) {
+	defer SetCellSize(CELL_SIZE)
+
+	theme, err := ParseTheme([]byte(`{"corner_radius": 0.1}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, size := range []int{60, 120} {
+		SetCellSize(size)
+		if actual, expected := theme.Radius(CELL_SIZE), float32(size)/10; actual != expected {
+			t.Errorf("expected a radius of %g on %d pixel cells, found %g", expected, size, actual)
+		}
+
+		style := ImageStyle(theme)
+		if actual, expected := style.Radius(IMAGE_CELL_SIZE), float64(IMAGE_CELL_SIZE)/10; actual != expected {
+			t.Errorf("expected board images to keep a radius of %g with %d pixel cells on screen, found %g", expected, size, actual)
+		}
+	}
+}
+
+func TestThemeImageColours(t *testing.T) {
+	theme, err := ParseTheme([]byte(`{"info_box": "#ffffff", "tiles": {"2048": {"fill": "#123456"}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if actual := theme.style.InfoText; ContrastRatio(actual, theme.style.InfoBox) < MIN_TEXT_CONTRAST {
+		t.Errorf("expected info text readable on the info box, found %v", actual)
+	}
+
+	if actual, expected := theme.style.Highlight, (color.NRGBA{0x12, 0x34, 0x56, 0xff}); actual != expected {
+		t.Errorf("expected the 2048 tile colour as highlight %v, found %v", expected, actual)
+	}
+}
+
+func TestBundledThemes(t *testing.T) {
+	themes, err := LoadThemes(filepath.Join("..", "..", THEME_DIR))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(themes) < 6 {
+		t.Fatalf("expected at least 6 bundled themes, found %d", len(themes))
+	}
+
+	for _, theme := range themes {
+		for val := 2; val <= 2*WIN_TILE; val *= 2 {
+			style := theme.Tile(val)
+			if ratio := ContrastRatio(style.Text, style.Fill); ratio < MIN_TEXT_CONTRAST {
+				t.Errorf("%s: text on %d has a contrast of only %.2f", theme.name, val, ratio)
+			}
+		}
+	}
+
+	classic := ClassicTheme()
+	for val, style := range classic.style.Tiles {
+		if themes[0].Tile(val) != style {
+			t.Errorf("bundled classic theme differs from the built in one for %d", val)
+		}
+	}
+}
+
+func TestContrastRatio(t *testing.T) {
+	black, white := color.NRGBA{0, 0, 0, 0xff}, color.NRGBA{0xff, 0xff, 0xff, 0xff}
+
+	if ratio := ContrastRatio(black, white); ratio < 20.99 || ratio > 21.01 {
+		t.Errorf("expected black on white to be 21, found %f", ratio)
+	}
+
+	if ratio := ContrastRatio(white, white); ratio != 1 {
+		t.Errorf("expected the same colour to be 1, found %f", ratio)
+	}
+}
+
+func TestReadableTextColor(t *testing.T) {
+	testCases := []struct {
+		name     string
+		fill     color.NRGBA
+		expected color.NRGBA
+	}{
+		{name: "light tile", fill: color.NRGBA{0xff, 0xff, 0xff, 0xff}, expected: TEXT_DARK},
+		{name: "dark tile", fill: DARK_GRAY, expected: TEXT_LIGHT},
+		{name: "mid tone", fill: RED, expected: color.NRGBA{0, 0, 0, 0xff}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := ReadableTextColor(tc.fill); actual != tc.expected {
+				t.Errorf("expected %v, found %v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRowTextColor(t *testing.T) {
+	themes, err := LoadThemes(filepath.Join("..", "..", THEME_DIR))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer SetTheme(CurrentTheme())
+
+	for _, theme := range themes {
+		t.Run(theme.name, func(t *testing.T) {
+			SetTheme(theme)
+
+			if actual := rowTextColor(false); actual != theme.text {
+				t.Errorf("expected rows to use the theme text %v, found %v", theme.text, actual)
+			}
+
+			if ratio := ContrastRatio(rowTextColor(true), theme.style.InfoBox); ratio < MIN_TEXT_CONTRAST {
+				t.Errorf("selected row text has a contrast of only %.2f", ratio)
+			}
+		})
+	}
+}
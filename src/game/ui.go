@@ -0,0 +1,578 @@
+package game
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+type LayoutDirection int32
+
+const (
+	UI_VERTICAL LayoutDirection = iota
+	UI_HORIZONTAL
+)
+
+// Everything the widgets react to during one tick
+// The pointer is the mouse or the first finger on a touchscreen
+type UIInput struct {
+	cursor   image.Point
+	held     bool
+	pressed  bool
+	released bool
+	up       bool
+	down     bool
+	left     bool
+	right    bool
+	next     bool
+	prev     bool
+	activate bool
+	back     bool
+}
+
+// A row or column the next widgets are placed in
+// Columns stack full width rows, rows split their width evenly between columns widgets
+type uiLayout struct {
+	direction LayoutDirection
+	area      image.Rectangle
+	columns   int
+	index     int
+	cursor    int
+}
+
+// Interaction of a widget during this tick
+type widgetState struct {
+	hot     bool
+	focused bool
+	pressed bool
+	clicked bool
+}
+
+// Immediate mode widgets
+// Screens call Begin, then a widget function for every widget and End in their Update every tick
+// The widget functions handle the input right away and return what happened, Draw replays what they drew
+type UI struct {
+	input      UIInput
+	lastCursor image.Point
+	pads       []Gamepad
+	layouts    []uiLayout
+	commands   []func(*ebiten.Image)
+	popups     []func(*ebiten.Image)
+	nextID     int
+	focus      int
+	focusable  []int
+	// The widget the pointer went down on, it gets the click if the pointer also goes up on it
+	active int
+	// Focus the next widget unless another one is focused already
+	wantFocus bool
+	// Open dropdown, its highlighted option and where its options are drawn
+	open      int
+	highlight int
+	popup     image.Rectangle
+	// Widgets outside a modal ignore the input while one was shown last tick
+	inModal   bool
+	modalSeen bool
+	modalOpen bool
+	// Navigation a widget used itself, e.g. left and right on a slider
+	consumed bool
+	// Only the mouse and touches reach the widgets, nothing takes the focus
+	pointerOnly bool
+	// Whether the pointer is over a widget this tick
+	hovered bool
+}
+
+func NewUI() *UI {
+	return &UI{}
+}
+
+// Widgets next to a board, the keys and gamepads keep moving the board and never reach them
+func NewPointerUI() *UI {
+	return &UI{pointerOnly: true}
+}
+
+// Height of a widget row for the current cell size
+func UIRowHeight() int {
+	return int(Fonts().Face(FONT_BODY).Size * 1.8)
+}
+
+// Height taken by rows widget rows in a column
+func UIHeight(rows int) int {
+	return rows*(UIRowHeight()+GAP) - GAP
+}
+
+// Reads the mouse, touches, keyboard and every gamepad in pads
+func ReadUIInput(pads []Gamepad) UIInput {
+	var in UIInput
+
+	x, y := ebiten.CursorPosition()
+	in.cursor = image.Pt(x, y)
+	in.held = ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft)
+	in.pressed = inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft)
+	in.released = inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonLeft)
+
+	if ids := ebiten.AppendTouchIDs(nil); len(ids) > 0 {
+		x, y := ebiten.TouchPosition(ids[0])
+		in.cursor, in.held = image.Pt(x, y), true
+	}
+	for _, id := range inpututil.AppendJustPressedTouchIDs(nil) {
+		x, y := ebiten.TouchPosition(id)
+		in.cursor, in.pressed = image.Pt(x, y), true
+	}
+
+	for _, id := range inpututil.AppendJustReleasedTouchIDs(nil) {
+		x, y := inpututil.TouchPositionInPreviousTick(id)
+		in.cursor, in.released = image.Pt(x, y), true
+	}
+
+	kb := Bindings()
+	shift := ebiten.IsKeyPressed(ebiten.KeyShift)
+	in.up = inpututil.IsKeyJustPressed(ebiten.KeyArrowUp) || kb.JustPressed(ACTION_UP)
+	in.down = inpututil.IsKeyJustPressed(ebiten.KeyArrowDown) || kb.JustPressed(ACTION_DOWN)
+	in.left = inpututil.IsKeyJustPressed(ebiten.KeyArrowLeft) || kb.JustPressed(ACTION_LEFT)
+	in.right = inpututil.IsKeyJustPressed(ebiten.KeyArrowRight) || kb.JustPressed(ACTION_RIGHT)
+	in.next = inpututil.IsKeyJustPressed(ebiten.KeyTab) && !shift
+	in.prev = inpututil.IsKeyJustPressed(ebiten.KeyTab) && shift
+	in.activate = inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeySpace)
+	in.back = inpututil.IsKeyJustPressed(ebiten.KeyEscape) || kb.JustPressed(ACTION_QUIT)
+
+	now := time.Now()
+	for i := range pads {
+		if d, ok := pads[i].Direction(now); ok {
+			switch d {
+			case UP:
+				in.up = true
+			case DOWN:
+				in.down = true
+			case LEFT:
+				in.left = true
+			case RIGHT:
+				in.right = true
+			}
+		}
+
+		in.activate = in.activate || pads[i].JustPressed(PAD_CONFIRM)
+		in.back = in.back || pads[i].JustPressed(PAD_BACK)
+	}
+
+	return in
+}
+
+// impl UI
+
+// Starts a tick with the current input, widgets are placed in a column filling area
+func (ui *UI) Begin(area image.Rectangle) {
+	if ui.pointerOnly {
+		in := ReadUIInput(nil)
+		ui.BeginWith(area, UIInput{cursor: in.cursor, held: in.held, pressed: in.pressed, released: in.released})
+		return
+	}
+
+	ui.connectGamepads()
+	ui.BeginWith(area, ReadUIInput(ui.pads))
+}
+
+// Every connected gamepad can use the widgets, held directions keep their repeat state
+func (ui *UI) connectGamepads() {
+	ids := ebiten.AppendGamepadIDs(nil)
+	pads := make([]Gamepad, 0, len(ids))
+
+	for _, id := range ids {
+		pad := Gamepad{id: id, connected: true}
+		for _, old := range ui.pads {
+			if old.id == id {
+				pad = old
+			}
+		}
+		pads = append(pads, pad)
+	}
+
+	ui.pads = pads
+}
+
+// Starts a tick with the given input
+func (ui *UI) BeginWith(area image.Rectangle, in UIInput) {
+	ui.input = in
+	ui.commands = ui.commands[:0]
+	ui.popups = ui.popups[:0]
+	ui.layouts = append(ui.layouts[:0], uiLayout{direction: UI_VERTICAL, area: area})
+	ui.nextID = 0
+	ui.focusable = ui.focusable[:0]
+	ui.consumed = false
+	ui.modalSeen = false
+	ui.hovered = false
+}
+
+// Moves the focus with the navigation nobody used and finishes the tick
+func (ui *UI) End() {
+	in := ui.input
+
+	if !slices.Contains(ui.focusable, ui.focus) && len(ui.focusable) > 0 {
+		ui.focus = ui.focusable[0]
+	}
+
+	if !ui.consumed && len(ui.focusable) > 0 {
+		i := slices.Index(ui.focusable, ui.focus)
+		n := len(ui.focusable)
+
+		switch {
+		case in.down || in.right || in.next:
+			ui.focus = ui.focusable[(i+1)%n]
+		case in.up || in.left || in.prev:
+			ui.focus = ui.focusable[(i-1+n)%n]
+		}
+	}
+
+	if !in.held {
+		ui.active = 0
+	}
+
+	ui.modalOpen = ui.modalSeen
+	ui.lastCursor = in.cursor
+}
+
+// True when back was pressed and no widget used it, e.g. to close an open dropdown
+func (ui *UI) Back() bool {
+	return ui.input.back && !ui.consumed
+}
+
+// True when the pointer is over a widget, so screens can ignore taps meant for the widgets
+func (ui *UI) Hovered() bool {
+	return ui.hovered
+}
+
+// Focuses the next widget unless one is focused already
+func (ui *UI) DefaultFocus() {
+	ui.wantFocus = true
+}
+
+// Starts a row of widgets that split the width of the next row evenly
+func (ui *UI) BeginRow(columns int) {
+	ui.layouts = append(ui.layouts, uiLayout{direction: UI_HORIZONTAL, area: ui.allocate(), columns: max(columns, 1)})
+}
+
+func (ui *UI) EndRow() {
+	if len(ui.layouts) > 1 {
+		ui.layouts = ui.layouts[:len(ui.layouts)-1]
+	}
+}
+
+// Rectangle of the next widget in the current layout
+func (ui *UI) allocate() image.Rectangle {
+	l := &ui.layouts[len(ui.layouts)-1]
+	rh := UIRowHeight()
+
+	if l.direction == UI_HORIZONTAL {
+		w := (l.area.Dx() - (l.columns-1)*GAP) / l.columns
+		x := l.area.Min.X + l.index*(w+GAP)
+		l.index++
+		return image.Rect(x, l.area.Min.Y, x+w, l.area.Max.Y)
+	}
+
+	y := l.area.Min.Y + l.cursor
+	l.cursor += rh + GAP
+	return image.Rect(l.area.Min.X, y, l.area.Max.X, y+rh)
+}
+
+// Places the next widget and works out how the pointer and focus interact with it
+func (ui *UI) widget(focusable bool) (int, image.Rectangle, widgetState) {
+	ui.nextID++
+	id := ui.nextID
+	r := ui.allocate()
+
+	if !focusable || (ui.modalOpen && !ui.inModal) {
+		return id, r, widgetState{}
+	}
+
+	if !ui.pointerOnly {
+		ui.focusable = append(ui.focusable, id)
+	}
+	if ui.wantFocus && !ui.pointerOnly {
+		ui.wantFocus = false
+		if ui.focus == 0 {
+			ui.focus = id
+		}
+	}
+
+	in := ui.input
+	var s widgetState
+	s.hot = in.cursor.In(r) && (ui.open == id || !in.cursor.In(ui.popup))
+	ui.hovered = ui.hovered || s.hot
+
+	// Moving the pointer over a widget focuses it, so mouse and keyboard never highlight two widgets
+	if s.hot && !ui.pointerOnly && (in.cursor != ui.lastCursor || in.pressed) {
+		ui.focus = id
+	}
+
+	if s.hot && in.pressed {
+		ui.active = id
+	}
+
+	s.focused = ui.focus == id
+	s.pressed = ui.active == id && in.held && s.hot
+	s.clicked = (ui.active == id && in.released && s.hot) || (s.focused && in.activate && !ui.consumed)
+	return id, r, s
+}
+
+func (ui *UI) draw(fn func(*ebiten.Image)) {
+	ui.commands = append(ui.commands, fn)
+}
+
+// Draws every widget of the last tick, open dropdowns on top
+func (ui *UI) Draw(screen *ebiten.Image) {
+	for _, fn := range ui.commands {
+		fn(screen)
+	}
+
+	for _, fn := range ui.popups {
+		fn(screen)
+	}
+}
+
+// Text centered in its row
+func (ui *UI) Label(label string) {
+	_, r, _ := ui.widget(false)
+	clr := CurrentTheme().text
+
+	ui.draw(func(screen *ebiten.Image) {
+		drawWidgetText(screen, label, r, clr)
+	})
+}
+
+// Returns true when the button was clicked or activated with the keyboard or a gamepad
+func (ui *UI) Button(label string) bool {
+	_, r, s := ui.widget(true)
+
+	ui.draw(func(screen *ebiten.Image) {
+		fill := widgetFill(s)
+		drawWidgetBox(screen, r, fill)
+		drawWidgetText(screen, label, r, ReadableTextColor(fill))
+	})
+
+	return s.clicked
+}
+
+// An on and off switch, left and right set it directly
+// Returns true when the value changed
+func (ui *UI) Toggle(label string, value *bool) bool {
+	_, r, s := ui.widget(true)
+	before := *value
+
+	if s.clicked {
+		*value = !*value
+	}
+
+	if s.focused && (ui.input.left || ui.input.right) {
+		*value = ui.input.right
+		ui.consumed = true
+	}
+
+	on := *value
+	ui.draw(func(screen *ebiten.Image) {
+		fill := widgetFill(s)
+		drawWidgetBox(screen, r, fill)
+
+		textArea := r
+		textArea.Max.X -= r.Dy() * 2
+		drawWidgetText(screen, label, textArea, ReadableTextColor(fill))
+
+		// The knob sits right when on
+		track := image.Rect(r.Max.X-r.Dy()*2, r.Min.Y+r.Dy()/4, r.Max.X-r.Dy()/4, r.Max.Y-r.Dy()/4)
+		trackFill := CurrentTheme().style.EmptyCell
+		if on {
+			trackFill = CurrentTheme().Tile(2048).Fill
+		}
+		fillRect(screen, track.Min.X, track.Min.Y, track.Dx(), track.Dy(), float32(track.Dy())/2, trackFill)
+
+		knob := track.Dy()
+		kx := track.Min.X
+		if on {
+			kx = track.Max.X - knob
+		}
+		fillRect(screen, kx, track.Min.Y, knob, knob, float32(knob)/2, TEXT_LIGHT)
+	})
+
+	return *value != before
+}
+
+// Picks a value between lo and hi in steps, by dragging, clicking the track or left and right
+// Returns true when the value changed
+func (ui *UI) Slider(label string, value *float64, lo float64, hi float64, step float64) bool {
+	id, r, s := ui.widget(true)
+	before := *value
+	track := image.Rect(r.Min.X+r.Dx()/2, r.Min.Y+r.Dy()*2/5, r.Max.X-r.Dy()/2, r.Max.Y-r.Dy()*2/5)
+
+	if ui.active == id && ui.input.held && track.Dx() > 0 {
+		t := float64(ui.input.cursor.X-track.Min.X) / float64(track.Dx())
+		*value = lo + min(max(t, 0), 1)*(hi-lo)
+	}
+
+	if s.focused && ui.input.left {
+		*value -= step
+		ui.consumed = true
+	}
+
+	if s.focused && ui.input.right {
+		*value += step
+		ui.consumed = true
+	}
+
+	if step > 0 {
+		*value = lo + math.Round((*value-lo)/step)*step
+	}
+	*value = min(max(*value, lo), hi)
+
+	t := 0.0
+	if hi > lo {
+		t = (*value - lo) / (hi - lo)
+	}
+
+	ui.draw(func(screen *ebiten.Image) {
+		fill := widgetFill(s)
+		drawWidgetBox(screen, r, fill)
+
+		textArea := r
+		textArea.Max.X = track.Min.X
+		drawWidgetText(screen, label, textArea, ReadableTextColor(fill))
+
+		fillRect(screen, track.Min.X, track.Min.Y, track.Dx(), track.Dy(), float32(track.Dy())/2, CurrentTheme().style.EmptyCell)
+		knob := r.Dy() / 2
+		kx := track.Min.X + int(t*float64(track.Dx())) - knob/2
+		fillRect(screen, kx, r.Min.Y+r.Dy()/4, knob, knob, float32(knob)/2, TEXT_LIGHT)
+	})
+
+	return *value != before
+}
+
+// Picks one of options from a list that opens below it, left and right step through them while closed
+// Returns true when the selection changed
+func (ui *UI) Dropdown(label string, options []string, selected *int) bool {
+	id, r, s := ui.widget(true)
+	before := *selected
+	in := ui.input
+
+	if len(options) == 0 {
+		return false
+	}
+
+	if ui.open == id {
+		ui.consumed = ui.consumed || in.up || in.down || in.activate || in.back
+		list := image.Rect(r.Min.X, r.Max.Y, r.Max.X, r.Max.Y+len(options)*r.Dy())
+
+		switch {
+		case in.up:
+			ui.highlight = (ui.highlight - 1 + len(options)) % len(options)
+		case in.down:
+			ui.highlight = (ui.highlight + 1) % len(options)
+		case in.activate:
+			*selected = ui.highlight
+			ui.closeDropdown()
+		case in.back:
+			ui.closeDropdown()
+		case in.cursor.In(list):
+			ui.highlight = (in.cursor.Y - list.Min.Y) / r.Dy()
+			if in.released || in.pressed {
+				*selected = ui.highlight
+				ui.closeDropdown()
+			}
+		case in.pressed && !in.cursor.In(r):
+			ui.closeDropdown()
+		}
+
+		if ui.open == id {
+			ui.popup = list
+			highlight := ui.highlight
+			ui.popups = append(ui.popups, func(screen *ebiten.Image) {
+				for i, option := range options {
+					row := image.Rect(list.Min.X, list.Min.Y+i*r.Dy(), list.Max.X, list.Min.Y+(i+1)*r.Dy())
+					fill := CurrentTheme().style.InfoBox
+					if i == highlight {
+						fill = Blend(fill, TEXT_LIGHT, 0.25)
+					}
+					fillRect(screen, row.Min.X, row.Min.Y, row.Dx(), row.Dy(), 0, fill)
+					drawWidgetText(screen, option, row, ReadableTextColor(fill))
+				}
+			})
+		}
+	} else if s.clicked {
+		ui.open, ui.highlight = id, *selected
+	} else if s.focused && (in.left || in.right) {
+		step := 1
+		if in.left {
+			step = -1
+		}
+		*selected = (*selected + step + len(options)) % len(options)
+		ui.consumed = true
+	}
+
+	current := options[min(max(*selected, 0), len(options)-1)]
+	ui.draw(func(screen *ebiten.Image) {
+		fill := widgetFill(s)
+		drawWidgetBox(screen, r, fill)
+		drawWidgetText(screen, label+": "+current, r, ReadableTextColor(fill))
+	})
+
+	return *selected != before
+}
+
+func (ui *UI) closeDropdown() {
+	ui.open = 0
+	ui.popup = image.Rectangle{}
+}
+
+// Dims everything drawn so far and starts a box in the middle of screen for rows widget rows under title
+// While a modal is shown only the widgets between BeginModal and EndModal take input
+func (ui *UI) BeginModal(screen image.Rectangle, title string, rows int) {
+	ui.inModal, ui.modalSeen = true, true
+
+	w := screen.Dx() * 2 / 3
+	h := UIHeight(rows+1) + 2*GAP
+	box := image.Rect(screen.Min.X+(screen.Dx()-w)/2, screen.Min.Y+(screen.Dy()-h)/2, 0, 0)
+	box.Max = box.Min.Add(image.Pt(w, h))
+
+	ui.draw(func(dst *ebiten.Image) {
+		theme := CurrentTheme()
+		fillRect(dst, screen.Min.X, screen.Min.Y, screen.Dx(), screen.Dy(), 0, theme.overlay)
+		fillRect(dst, box.Min.X, box.Min.Y, box.Dx(), box.Dy(), theme.Radius(CELL_SIZE), theme.style.Board)
+	})
+
+	ui.layouts = append(ui.layouts, uiLayout{direction: UI_VERTICAL, area: box.Inset(GAP)})
+	ui.Label(title)
+}
+
+func (ui *UI) EndModal() {
+	ui.inModal = false
+	if len(ui.layouts) > 1 {
+		ui.layouts = ui.layouts[:len(ui.layouts)-1]
+	}
+}
+
+// end
+
+// Fill of a focusable widget, lighter when focused or under the pointer and darker while pressed
+func widgetFill(s widgetState) color.NRGBA {
+	fill := CurrentTheme().style.InfoBox
+	switch {
+	case s.pressed:
+		return Blend(fill, color.NRGBA{0, 0, 0, 0xff}, 0.25)
+	case s.focused || s.hot:
+		return Blend(fill, TEXT_LIGHT, 0.25)
+	}
+
+	return fill
+}
+
+func drawWidgetBox(screen *ebiten.Image, r image.Rectangle, fill color.NRGBA) {
+	fillRect(screen, r.Min.X, r.Min.Y, r.Dx(), r.Dy(), CurrentTheme().Radius(r.Dy()), fill)
+}
+
+func drawWidgetText(screen *ebiten.Image, s string, r image.Rectangle, clr color.Color) {
+	txtOp := &text.DrawOptions{}
+	txtOp.ColorScale.ScaleWithColor(clr)
+	DrawCenteredText(screen, Fonts().Face(FONT_BODY), s, r.Min.X+r.Dx()/2, r.Min.Y+r.Dy()/2, txtOp)
+}
@@ -0,0 +1,281 @@
+package game
+
+import (
+	"image"
+	"testing"
+)
+
+var uiArea = image.Rect(0, 0, 400, 400)
+
+// Center of the widget in row of a column in uiArea
+func rowCenter(row int) image.Point {
+	return image.Pt(uiArea.Dx()/2, row*(UIRowHeight()+GAP)+UIRowHeight()/2)
+}
+
+func TestUIButton(t *testing.T) {
+	testCases := []struct {
+		name     string
+		inputs   []UIInput
+		expected bool
+	}{
+		{
+			name:     "click",
+			inputs:   []UIInput{{cursor: rowCenter(1), held: true, pressed: true}, {cursor: rowCenter(1), released: true}},
+			expected: true,
+		},
+		{
+			name:     "press only",
+			inputs:   []UIInput{{cursor: rowCenter(1), held: true, pressed: true}},
+			expected: false,
+		},
+		{
+			name:     "released elsewhere",
+			inputs:   []UIInput{{cursor: rowCenter(1), held: true, pressed: true}, {cursor: rowCenter(0), released: true}},
+			expected: false,
+		},
+		{
+			name:     "pressed elsewhere",
+			inputs:   []UIInput{{cursor: rowCenter(0), held: true, pressed: true}, {cursor: rowCenter(1), released: true}},
+			expected: false,
+		},
+		{
+			name:     "keyboard",
+			inputs:   []UIInput{{down: true}, {activate: true}},
+			expected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ui := NewUI()
+			clicked := false
+
+			for _, in := range tc.inputs {
+				ui.BeginWith(uiArea, in)
+				ui.Button("first")
+				clicked = ui.Button("second")
+				ui.End()
+			}
+
+			if clicked != tc.expected {
+				t.Errorf("expected clicked to be %t, found %t", tc.expected, clicked)
+			}
+		})
+	}
+}
+
+func TestUIFocus(t *testing.T) {
+	ui := NewUI()
+
+	// Each step builds on the focus left by the one before
+	testCases := []struct {
+		name     string
+		input    UIInput
+		expected int
+	}{
+		{name: "default focus", input: UIInput{}, expected: 3},
+		{name: "down", input: UIInput{down: true}, expected: 4},
+		{name: "down wraps around", input: UIInput{down: true}, expected: 1},
+		{name: "prev wraps around", input: UIInput{prev: true}, expected: 4},
+		{name: "pointer", input: UIInput{cursor: rowCenter(0)}, expected: 1},
+	}
+
+	for _, tc := range testCases {
+		ui.BeginWith(uiArea, tc.input)
+		ui.Button("a")
+		ui.Label("not focusable")
+		ui.DefaultFocus()
+		ui.Button("b")
+		ui.Button("c")
+		ui.End()
+
+		if ui.focus != tc.expected {
+			t.Errorf("%s: expected widget %d to be focused, found %d", tc.name, tc.expected, ui.focus)
+		}
+	}
+}
+
+func TestUIToggle(t *testing.T) {
+	testCases := []struct {
+		name     string
+		start    bool
+		input    UIInput
+		expected bool
+	}{
+		{name: "activate", start: false, input: UIInput{activate: true}, expected: true},
+		{name: "right", start: false, input: UIInput{right: true}, expected: true},
+		{name: "left", start: true, input: UIInput{left: true}, expected: false},
+		{name: "left while off", start: false, input: UIInput{left: true}, expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ui := NewUI()
+			value := tc.start
+
+			// The first tick focuses the toggle
+			ui.BeginWith(uiArea, UIInput{})
+			ui.Toggle("sound", &value)
+			ui.End()
+
+			ui.BeginWith(uiArea, tc.input)
+			ui.Toggle("sound", &value)
+			ui.End()
+
+			if value != tc.expected {
+				t.Errorf("expected %t, found %t", tc.expected, value)
+			}
+		})
+	}
+}
+
+func TestUISlider(t *testing.T) {
+	track := func(x int) image.Point {
+		r := image.Rect(uiArea.Min.X, 0, uiArea.Max.X, UIRowHeight())
+		return image.Pt(r.Min.X+r.Dx()/2+x, r.Dy()/2)
+	}
+
+	testCases := []struct {
+		name     string
+		start    float64
+		inputs   []UIInput
+		expected float64
+	}{
+		{name: "step right", start: 0.5, inputs: []UIInput{{right: true}}, expected: 0.6},
+		{name: "step left", start: 0.5, inputs: []UIInput{{left: true}}, expected: 0.4},
+		{name: "clamped high", start: 1, inputs: []UIInput{{right: true}}, expected: 1},
+		{name: "clamped low", start: 0, inputs: []UIInput{{left: true}}, expected: 0},
+		{name: "snapped", start: 0.33, inputs: []UIInput{{}}, expected: 0.3},
+		{
+			name:     "pressed on the start",
+			start:    0.5,
+			inputs:   []UIInput{{cursor: track(0), held: true, pressed: true}},
+			expected: 0,
+		},
+		{
+			name:     "dragged past the end",
+			start:    0.5,
+			inputs:   []UIInput{{cursor: track(0), held: true, pressed: true}, {cursor: track(uiArea.Dx()), held: true}},
+			expected: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ui := NewUI()
+			value := tc.start
+
+			for _, in := range append([]UIInput{{}}, tc.inputs...) {
+				ui.BeginWith(uiArea, in)
+				ui.Slider("volume", &value, 0, 1, 0.1)
+				ui.End()
+			}
+
+			if value < tc.expected-1e-9 || value > tc.expected+1e-9 {
+				t.Errorf("expected %v, found %v", tc.expected, value)
+			}
+		})
+	}
+}
+
+func TestUIDropdown(t *testing.T) {
+	ui := NewUI()
+	options := []string{"small", "medium", "large"}
+	selected := 0
+
+	// Each step builds on the dropdown left by the one before
+	testCases := []struct {
+		name             string
+		input            UIInput
+		expectedSelected int
+		expectedOpen     bool
+		expectedFocus    int
+	}{
+		{name: "focus", input: UIInput{}, expectedSelected: 0, expectedOpen: false, expectedFocus: 1},
+		{name: "open", input: UIInput{activate: true}, expectedSelected: 0, expectedOpen: true, expectedFocus: 1},
+		{name: "next option", input: UIInput{down: true}, expectedSelected: 0, expectedOpen: true, expectedFocus: 1},
+		{name: "last option keeps the focus", input: UIInput{down: true}, expectedSelected: 0, expectedOpen: true, expectedFocus: 1},
+		{name: "pick", input: UIInput{activate: true}, expectedSelected: 2, expectedOpen: false, expectedFocus: 1},
+		{name: "open again", input: UIInput{activate: true}, expectedSelected: 2, expectedOpen: true, expectedFocus: 1},
+		// The list covers the button below
+		{
+			name:             "pick with the pointer",
+			input:            UIInput{cursor: rowCenter(1), held: true, pressed: true},
+			expectedSelected: 0,
+			expectedOpen:     false,
+			expectedFocus:    2,
+		},
+		{name: "right on a closed dropdown", input: UIInput{right: true}, expectedSelected: 1, expectedOpen: false, expectedFocus: 1},
+	}
+
+	for _, tc := range testCases {
+		ui.BeginWith(uiArea, tc.input)
+		ui.Dropdown("size", options, &selected)
+		ui.Button("below")
+		ui.End()
+
+		if selected != tc.expectedSelected || (ui.open != 0) != tc.expectedOpen || ui.focus != tc.expectedFocus {
+			t.Errorf("%s: expected option %d, open %t and focus %d, found %d, %t and %d",
+				tc.name, tc.expectedSelected, tc.expectedOpen, tc.expectedFocus, selected, ui.open != 0, ui.focus)
+		}
+	}
+}
+
+func TestUIModal(t *testing.T) {
+	ui := NewUI()
+
+	// Each step builds on the one before
+	testCases := []struct {
+		name            string
+		input           UIInput
+		expectedOutside bool
+		expectedInside  bool
+	}{
+		{name: "focus", input: UIInput{}, expectedOutside: false, expectedInside: false},
+		{name: "press outside", input: UIInput{cursor: rowCenter(0), held: true, pressed: true}, expectedOutside: false, expectedInside: false},
+		{name: "release outside", input: UIInput{cursor: rowCenter(0), released: true}, expectedOutside: false, expectedInside: false},
+		{name: "activate", input: UIInput{activate: true}, expectedOutside: false, expectedInside: true},
+	}
+
+	for _, tc := range testCases {
+		ui.BeginWith(uiArea, tc.input)
+		outside := ui.Button("outside")
+		ui.BeginModal(uiArea, "Sure?", 1)
+		inside := ui.Button("inside")
+		ui.EndModal()
+		ui.End()
+
+		if outside != tc.expectedOutside || inside != tc.expectedInside {
+			t.Errorf("%s: expected outside %t and inside %t, found %t and %t", tc.name, tc.expectedOutside, tc.expectedInside, outside, inside)
+		}
+	}
+}
+
+func TestPointerUI(t *testing.T) {
+	ui := NewPointerUI()
+
+	// Each step builds on the one before
+	testCases := []struct {
+		name            string
+		input           UIInput
+		expectedClicked bool
+		expectedHovered bool
+	}{
+		{name: "idle", input: UIInput{cursor: rowCenter(3)}, expectedClicked: false, expectedHovered: false},
+		{name: "keyboard never focuses", input: UIInput{cursor: rowCenter(3), activate: true}, expectedClicked: false, expectedHovered: false},
+		{name: "press", input: UIInput{cursor: rowCenter(0), held: true, pressed: true}, expectedClicked: false, expectedHovered: true},
+		{name: "release", input: UIInput{cursor: rowCenter(0), released: true}, expectedClicked: true, expectedHovered: true},
+		{name: "away", input: UIInput{cursor: rowCenter(3)}, expectedClicked: false, expectedHovered: false},
+	}
+
+	for _, tc := range testCases {
+		ui.BeginWith(uiArea, tc.input)
+		clicked := ui.Button("New game")
+		ui.End()
+
+		if clicked != tc.expectedClicked || ui.Hovered() != tc.expectedHovered || ui.focus != 0 {
+			t.Errorf("%s: expected clicked %t and hovered %t without focus, found %t, %t and focus %d",
+				tc.name, tc.expectedClicked, tc.expectedHovered, clicked, ui.Hovered(), ui.focus)
+		}
+	}
+}
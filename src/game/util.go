@@ -6,6 +6,11 @@ import (
 )
 
 func GetRandomCell(cells [][]Cell) (Cell, error) {
+	return pickRandomCell(cells, rand.IntN)
+}
+
+// Picks an empty cell using the given source of random indexes
+func pickRandomCell(cells [][]Cell, intN func(int) int) (Cell, error) {
 	if len(cells) < 1 {
 		return Cell{}, errors.New("could not determine grid size")
 	}
@@ -16,7 +21,7 @@ func GetRandomCell(cells [][]Cell) (Cell, error) {
 		for j := range row {
 			cell := &cells[i][j]
 
-			if !cell.isRendered {
+			if !cell.isRendered && !cell.isBlocked {
 				newCell := Cell{pos_x: i, pos_y: j}
 				emptyCells = append(emptyCells, newCell)
 			}
@@ -27,7 +32,7 @@ func GetRandomCell(cells [][]Cell) (Cell, error) {
 		return Cell{}, errors.New("no empty cells found")
 	}
 
-	return emptyCells[rand.IntN(len(emptyCells))], nil
+	return emptyCells[intN(len(emptyCells))], nil
 }
 
 // From a boolean array find first empty from given index(START/END)
@@ -74,6 +79,7 @@ func TakeVerticalSlice(cells [][]Cell, col int) ([]*Cell, error) {
 	return v_slice, nil
 }
 
+// Swaps the tiles of two cells, the tile keeps track of where it came from
 func ChangeCellState(src *Cell, dst *Cell) {
 	tmpRendered := dst.isRendered
 	tmpVal := dst.val
@@ -83,13 +89,17 @@ func ChangeCellState(src *Cell, dst *Cell) {
 
 	src.val = tmpVal
 	src.isRendered = tmpRendered
+
+	src.origin, dst.origin = dst.origin, src.origin
+	src.mergeOrigin, dst.mergeOrigin = dst.mergeOrigin, src.mergeOrigin
+	src.merged, dst.merged = dst.merged, src.merged
 }
 
 func HasEmptyCell(cells [][]Cell) bool {
 	for i := 0; i < len(cells); i++ {
 		for j := 0; j < len(cells[i]); j++ {
 			c := cells[i][j]
-			if !c.isRendered {
+			if !c.isRendered && !c.isBlocked {
 				return true
 			}
 		}
@@ -97,3 +107,31 @@ func HasEmptyCell(cells [][]Cell) bool {
 
 	return false
 }
+
+// Largest rendered tile on the board, 0 if the board is empty
+func MaxTile(cells [][]Cell) int {
+	maxVal := 0
+	for _, row := range cells {
+		for _, c := range row {
+			if c.isRendered && c.val > maxVal {
+				maxVal = c.val
+			}
+		}
+	}
+
+	return maxVal
+}
+
+// Finds the board cell under the given screen position
+// Returns the row and column of the cell, false if the position is outside of every cell
+func CellAt(b Board, x int, y int) (int, int, bool) {
+	for i, row := range b.cells {
+		for j, c := range row {
+			if x >= c.x && x < c.x+CELL_SIZE && y >= c.y && y < c.y+CELL_SIZE {
+				return i, j, true
+			}
+		}
+	}
+
+	return 0, 0, false
+}
@@ -0,0 +1,190 @@
+package game
+
+import (
+	"fmt"
+	"image"
+	"math/rand/v2"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// Two boards side by side, player 1 on WASD and player 2 on the arrow keys
+// winner is the index of the winning player, -1 while playing and for a draw
+type VersusGame struct {
+	app      *App
+	players  []*Game
+	sameSeed bool
+	target   int
+	winner   int
+	finished bool
+}
+
+func NewVersusGame(app *App, target int, sameSeed bool) *VersusGame {
+	vg := VersusGame{app: app, target: target, sameSeed: sameSeed}
+	vg.reset()
+
+	return &vg
+}
+
+// Decides the winner once one player reaches the target or gets stuck
+// Returns false while the match is still going on
+func VersusWinner(players []*Game) (int, bool) {
+	finished, over := -1, 0
+	for i, p := range players {
+		switch p.status {
+		case FINISHED:
+			if finished < 0 || p.score > players[finished].score {
+				finished = i
+			}
+		case GAME_OVER:
+			over++
+		}
+	}
+
+	if finished >= 0 {
+		return finished, true
+	}
+
+	if over == 0 {
+		return -1, false
+	}
+
+	// The last player still on the board wins
+	if over < len(players) {
+		if over == len(players)-1 {
+			for i, p := range players {
+				if p.status == RUNNING {
+					return i, true
+				}
+			}
+		}
+
+		return -1, false
+	}
+
+	// Everyone is stuck, the higher score wins
+	best, draw := 0, false
+	for i, p := range players[1:] {
+		if p.score > players[best].score {
+			best, draw = i+1, false
+		} else if p.score == players[best].score {
+			draw = true
+		}
+	}
+
+	if draw {
+		return -1, true
+	}
+
+	return best, true
+}
+
+func (vg *VersusGame) reset() {
+	keys := []KeyBindings{WASD_KEYS, ARROW_KEYS}
+	seed := rand.Uint64()
+
+	vg.players = make([]*Game, len(keys))
+	for i := range vg.players {
+		spawner := &RandomSpawner{}
+		if vg.sameSeed {
+			spawner = NewSeededSpawner(seed)
+		}
+		spawner.fourChance = FOUR_CHANCE
+
+		g := NewGame(NewBoardAt(CELL_COUNT, 0, 0), spawner)
+		g.target = vg.target
+		g.keys = keys[i]
+		PlaceSpawn(g)
+		vg.players[i] = g
+	}
+
+	vg.winner = -1
+	vg.finished = false
+	vg.Resize(ScreenSize())
+}
+
+// Gives every player an equal share of the screen, side by side in landscape and stacked in portrait
+func (vg *VersusGame) Resize(width int, height int) {
+	n := len(vg.players)
+	for i, g := range vg.players {
+		area := image.Rect(width*i/n, 0, width*(i+1)/n, height)
+		if height > width {
+			area = image.Rect(0, height*i/n, width, height*(i+1)/n)
+		}
+
+		ApplyLayout(g, area)
+	}
+}
+
+func (vg *VersusGame) Update() error {
+	if Bindings().JustPressed(ACTION_QUIT) {
+		vg.app.Pop()
+		return nil
+	}
+
+	for _, p := range vg.players {
+		AdvanceAnimations(p)
+	}
+	AssignGamepads(vg.players)
+
+	for _, p := range vg.players {
+		if p.gamepad.JustPressed(PAD_MENU) {
+			vg.app.Pop()
+			return nil
+		}
+	}
+
+	if vg.finished {
+		rematch := inpututil.IsKeyJustPressed(ebiten.KeyR)
+		for _, p := range vg.players {
+			rematch = rematch || p.gamepad.JustPressed(PAD_NEW_GAME)
+		}
+
+		if rematch {
+			vg.reset()
+		}
+		return nil
+	}
+
+	for _, p := range vg.players {
+		if p.status == RUNNING {
+			updateRunning(p)
+		}
+	}
+
+	vg.winner, vg.finished = VersusWinner(vg.players)
+	return nil
+}
+
+func (vg *VersusGame) Draw(screen *ebiten.Image) {
+	for i, g := range vg.players {
+		drawBackground(g, screen)
+		drawBoard(g, screen)
+
+		x, y := InfoBoxPosition(g, 0)
+		drawInfoBox(g, screen, x, y, fmt.Sprintf("PLAYER %d", i+1), fmt.Sprint(g.score))
+		drawScoreEffects(g, screen, x, y)
+
+		if !vg.finished {
+			if g.status == GAME_OVER {
+				drawOverlay(g, screen)
+			}
+			continue
+		}
+
+		message := "Draw!"
+		if vg.winner == i {
+			message = "Winner!"
+		} else if vg.winner >= 0 {
+			message = "Defeated"
+		}
+
+		drawOverlay(g, screen)
+		drawAfterGameText(g, screen, message, "R for a rematch")
+	}
+}
+
+func (vg *VersusGame) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
+	return ScaledSize(outsideWidth, outsideHeight)
+}
@@ -0,0 +1,67 @@
+package game
+
+import "testing"
+
+type VersusWinnerTest struct {
+	name             string
+	players          []*Game
+	expectedWinner   int
+	expectedFinished bool
+}
+
+func TestVersusWinner(t *testing.T) {
+	testCases := []VersusWinnerTest{
+		{
+			name:             "both playing",
+			players:          []*Game{{status: RUNNING}, {status: RUNNING}},
+			expectedWinner:   -1,
+			expectedFinished: false,
+		},
+		{
+			name:             "second player reaches the target",
+			players:          []*Game{{status: RUNNING}, {status: FINISHED}},
+			expectedWinner:   1,
+			expectedFinished: true,
+		},
+		{
+			name:             "first player gets stuck",
+			players:          []*Game{{status: GAME_OVER, score: 500}, {status: RUNNING, score: 10}},
+			expectedWinner:   1,
+			expectedFinished: true,
+		},
+		{
+			name:             "both stuck, higher score wins",
+			players:          []*Game{{status: GAME_OVER, score: 500}, {status: GAME_OVER, score: 10}},
+			expectedWinner:   0,
+			expectedFinished: true,
+		},
+		{
+			name:             "both stuck with the same score",
+			players:          []*Game{{status: GAME_OVER, score: 10}, {status: GAME_OVER, score: 10}},
+			expectedWinner:   -1,
+			expectedFinished: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actualWinner, actualFinished := VersusWinner(tc.players)
+			if actualWinner != tc.expectedWinner || actualFinished != tc.expectedFinished {
+				t.Errorf("expected %d, %t, found %d, %t", tc.expectedWinner, tc.expectedFinished, actualWinner, actualFinished)
+			}
+		})
+	}
+}
+
+func TestSeededSpawnerIsFair(t *testing.T) {
+	first, second := NewSeededSpawner(42), NewSeededSpawner(42)
+	cells := emptyCells(4)
+
+	for range 10 {
+		a, errA := first.NextSpawn(cells)
+		b, errB := second.NextSpawn(cells)
+		if errA != nil || errB != nil || a != b {
+			t.Fatalf("spawners with the same seed differ: %+v, %+v", a, b)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package replay
+
+import (
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"io"
+	"os"
+	"time"
+)
+
+// How an animated GIF of a recording looks
+// highlight marks the edge of the board the next move pushes the tiles against
+type GIFOptions struct {
+	delay     time.Duration
+	cellSize  int
+	highlight bool
+}
+
+// How long every board is shown and how large its cells are
+var GIF_DELAY = time.Second / 2
+var GIF_CELL_SIZE = 60
+
+// The last board stays up this many times as long as the others before the GIF loops
+var GIF_LAST_FRAME_HOLD = 4
+
+func DefaultGIFOptions() GIFOptions {
+	return GIFOptions{delay: GIF_DELAY, cellSize: GIF_CELL_SIZE, highlight: true}
+}
+
+func NewGIFOptions(delay time.Duration, cellSize int, highlight bool) GIFOptions {
+	return GIFOptions{delay: delay, cellSize: cellSize, highlight: highlight}
+}
+
+// Renders every board of the recording in its style into a looping GIF
+func RenderGIF(r Recording, opts GIFOptions) *gif.GIF {
+	pal := gifPalette(&r.style)
+	delay := max(1, int(opts.delay/(10*time.Millisecond)))
+	out := &gif.GIF{}
+
+	for i, frame := range r.frames {
+		img := RenderBoard(frame.Board, frame.Score, &r.style, opts.cellSize)
+		if opts.highlight && frame.Move != "" {
+			edge := geometryOf(len(frame.Board), opts.cellSize).edge(frame.Move)
+			draw.Draw(img, edge, image.NewUniform(r.style.Highlight), image.Point{}, draw.Src)
+		}
+
+		paletted := image.NewPaletted(img.Bounds(), pal)
+		draw.Draw(paletted, paletted.Bounds(), img, image.Point{}, draw.Src)
+
+		out.Image = append(out.Image, paletted)
+		if i == len(r.frames)-1 {
+			out.Delay = append(out.Delay, delay*GIF_LAST_FRAME_HOLD)
+		} else {
+			out.Delay = append(out.Delay, delay)
+		}
+	}
+
+	return out
+}
+
+func EncodeGIF(w io.Writer, r Recording, opts GIFOptions) error {
+	return gif.EncodeAll(w, RenderGIF(r, opts))
+}
+
+// Writes the recording as an animated GIF to path
+func SaveGIF(path string, r Recording, opts GIFOptions) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := EncodeGIF(f, r, opts); err != nil {
+		return err
+	}
+
+	return f.Close()
+}
+
+// The colours of the style first so they come out exact, the rest of the palette covers anti aliased text
+func gifPalette(style *Style) color.Palette {
+	pal := color.Palette{}
+	add := func(c color.Color) {
+		if len(pal) < 256 && pal.Convert(c) != c {
+			pal = append(pal, c)
+		}
+	}
+
+	add(style.Board)
+	add(style.EmptyCell)
+	add(style.BlockedCell)
+	add(style.InfoBox)
+	add(style.InfoLabel)
+	add(style.InfoText)
+	add(style.Highlight)
+	for val := 2; val <= 131072; val *= 2 {
+		add(style.Tile(val).Fill)
+		add(style.Tile(val).Text)
+	}
+
+	for _, c := range palette.Plan9 {
+		if len(pal) == 256 {
+			break
+		}
+		add(c)
+	}
+
+	return pal
+}
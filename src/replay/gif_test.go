@@ -0,0 +1,50 @@
+package replay
+
+import (
+	"bytes"
+	"image/color"
+	"image/gif"
+	"testing"
+	"time"
+)
+
+func TestRenderGIF(t *testing.T) {
+	r := testRecording()
+
+	testCases := []struct {
+		name      string
+		highlight bool
+	}{
+		{name: "plain", highlight: false},
+		{name: "highlighted", highlight: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			out := RenderGIF(r, NewGIFOptions(200*time.Millisecond, 48, tc.highlight))
+			if len(out.Image) != 2 || out.Delay[0] != 20 || out.Delay[1] != 20*GIF_LAST_FRAME_HOLD {
+				t.Fatalf("expected 2 frames of 20 and %d, found %d frames with %v", 20*GIF_LAST_FRAME_HOLD, len(out.Image), out.Delay)
+			}
+
+			// 4 cells of 48 and 5 gaps of 4
+			if b := out.Image[0].Bounds(); b.Dx() != 212 {
+				t.Errorf("expected frames 212 wide, found %v", b)
+			}
+
+			// The right edge of the board shows the move to the right
+			edge := out.Image[0].At(210, out.Image[0].Bounds().Dy()-100)
+			marked := color.NRGBAModel.Convert(edge) == color.NRGBAModel.Convert(r.Style().Highlight)
+			if marked != tc.highlight {
+				t.Errorf("expected the move to be highlighted: %t, found %v", tc.highlight, edge)
+			}
+
+			var buf bytes.Buffer
+			if err := gif.EncodeAll(&buf, out); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := gif.DecodeAll(&buf); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package replay
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// Where things go on a board image, the score box above the board
+type boardGeometry struct {
+	cellSize int
+	gap      int
+	header   int
+	board    image.Rectangle
+}
+
+// Alpha mask of a rectangle with rounded corners
+type roundedRect struct {
+	r      image.Rectangle
+	radius float64
+}
+
+// Faces of the built in Go font by size, board images look the same whatever font the theme uses
+var imageFaces = map[float64]font.Face{}
+
+// Geometry of an image of a board with the given number of cells per side
+func geometryOf(cells int, cellSize int) boardGeometry {
+	gap := max(1, cellSize/12)
+	header := cellSize * 3 / 4
+	size := cells*cellSize + (cells+1)*gap
+
+	return boardGeometry{cellSize: cellSize, gap: gap, header: header, board: image.Rect(0, header, size, header+size)}
+}
+
+// Renders a board and its score on the CPU with image/draw, no window or GPU is needed
+// board holds the values row by row, 0 means empty and BLOCKED_VALUE means blocked
+func RenderBoard(board [][]int, score int, style *Style, cellSize int) *image.RGBA {
+	geo := geometryOf(len(board), cellSize)
+	gap := geo.gap
+	radius := style.Radius(cellSize)
+
+	img := image.NewRGBA(image.Rect(0, 0, geo.board.Max.X, geo.board.Max.Y))
+	draw.Draw(img, img.Bounds(), image.NewUniform(style.Board), image.Point{}, draw.Src)
+
+	// Score box in the top left corner, label above the value like the info boxes on screen
+	box := image.Rect(gap, gap, gap+cellSize*2, geo.header)
+	fillRoundedRect(img, box, radius, style.InfoBox)
+	label := imageFace(float64(cellSize) / 5)
+	drawCenteredString(img, label, "SCORE", box.Min.X+box.Dx()/2, box.Min.Y+box.Dy()/4, style.InfoLabel)
+	drawCenteredString(img, label, strconv.Itoa(score), box.Min.X+box.Dx()/2, box.Min.Y+box.Dy()*3/4, style.InfoText)
+
+	for i, row := range board {
+		for j, val := range row {
+			r := geo.cell(i, j)
+
+			switch {
+			case val == BLOCKED_VALUE:
+				fillRoundedRect(img, r, radius, style.BlockedCell)
+			case val > 0:
+				tile := style.Tile(val)
+				fillRoundedRect(img, r, radius, tile.Fill)
+				s := strconv.Itoa(val)
+				drawCenteredString(img, tileImageFace(s, cellSize, gap), s, r.Min.X+cellSize/2, r.Min.Y+cellSize/2, tile.Text)
+			default:
+				fillRoundedRect(img, r, radius, style.EmptyCell)
+			}
+		}
+	}
+
+	return img
+}
+
+// Writes img as a PNG into dir, named after when it was taken, and returns its path
+func SavePNG(img image.Image, dir string, now time.Time) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, "2048_"+now.Format("2006-01-02_15-04-05.000")+".png")
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if err := png.Encode(f, img); err != nil {
+		return "", err
+	}
+
+	return path, f.Close()
+}
+
+func fillRoundedRect(dst draw.Image, r image.Rectangle, radius float64, clr color.Color) {
+	radius = min(radius, float64(r.Dx())/2, float64(r.Dy())/2)
+	draw.DrawMask(dst, r, image.NewUniform(clr), image.Point{}, roundedRect{r: r, radius: radius}, r.Min, draw.Over)
+}
+
+func imageFace(size float64) font.Face {
+	if face, ok := imageFaces[size]; ok {
+		return face
+	}
+
+	f, err := opentype.Parse(goregular.TTF)
+	if err != nil {
+		log.Fatal("failed to load font:", err)
+	}
+
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		log.Fatal("failed to load font:", err)
+	}
+
+	imageFaces[size] = face
+	return face
+}
+
+// Face for a tile label, long values shrink until they fit inside the tile with a gap on both sides
+func tileImageFace(label string, cellSize int, gap int) font.Face {
+	size := float64(cellSize) / 3
+	face := imageFace(size)
+
+	maxWidth := cellSize - 2*gap
+	if w := font.MeasureString(face, label).Ceil(); w > maxWidth {
+		face = imageFace(float64(int(size * float64(maxWidth) / float64(w))))
+	}
+
+	return face
+}
+
+func drawCenteredString(dst draw.Image, face font.Face, s string, cx int, cy int, clr color.Color) {
+	m := face.Metrics()
+	w := font.MeasureString(face, s)
+	d := font.Drawer{
+		Dst:  dst,
+		Src:  image.NewUniform(clr),
+		Face: face,
+		Dot:  fixed.Point26_6{X: fixed.I(cx) - w/2, Y: fixed.I(cy) + (m.Ascent-m.Descent)/2},
+	}
+	d.DrawString(s)
+}
+
+// impl boardGeometry
+
+// Rectangle of the cell in row i and column j
+func (geo boardGeometry) cell(i int, j int) image.Rectangle {
+	x := geo.board.Min.X + geo.gap + j*(geo.cellSize+geo.gap)
+	y := geo.board.Min.Y + geo.gap + i*(geo.cellSize+geo.gap)
+	return image.Rect(x, y, x+geo.cellSize, y+geo.cellSize)
+}
+
+// The gap along the edge of the board that a move in direction pushes the tiles against
+func (geo boardGeometry) edge(direction string) image.Rectangle {
+	b := geo.board
+	switch direction {
+	case "up":
+		return image.Rect(b.Min.X, b.Min.Y, b.Max.X, b.Min.Y+geo.gap)
+	case "right":
+		return image.Rect(b.Max.X-geo.gap, b.Min.Y, b.Max.X, b.Max.Y)
+	case "down":
+		return image.Rect(b.Min.X, b.Max.Y-geo.gap, b.Max.X, b.Max.Y)
+	case "left":
+		return image.Rect(b.Min.X, b.Min.Y, b.Min.X+geo.gap, b.Max.Y)
+	}
+
+	return image.Rectangle{}
+}
+
+// end
+
+// impl roundedRect
+
+func (rr roundedRect) ColorModel() color.Model {
+	return color.AlphaModel
+}
+
+func (rr roundedRect) Bounds() image.Rectangle {
+	return rr.r
+}
+
+// Opaque inside the rectangle, transparent outside the circles that round its corners
+func (rr roundedRect) At(x int, y int) color.Color {
+	px, py := float64(x)+0.5, float64(y)+0.5
+	cx := min(max(px, float64(rr.r.Min.X)+rr.radius), float64(rr.r.Max.X)-rr.radius)
+	cy := min(max(py, float64(rr.r.Min.Y)+rr.radius), float64(rr.r.Max.Y)-rr.radius)
+
+	if dx, dy := px-cx, py-cy; dx*dx+dy*dy > rr.radius*rr.radius {
+		return color.Alpha{}
+	}
+
+	return color.Alpha{A: 0xff}
+}
+
+// end
@@ -0,0 +1,138 @@
+package replay
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderBoard(t *testing.T) {
+	style := testStyle()
+	style.CornerRadius = 1.0 / 3
+	img := RenderBoard([][]int{{2, 131072}, {BLOCKED_VALUE, 0}}, 1234, &style, 60)
+
+	// 2 cells of 60 and 3 gaps of 5, with a header of 45 above
+	if b := img.Bounds(); b.Dx() != 135 || b.Dy() != 180 {
+		t.Fatalf("expected a 135x180 image, found %v", b)
+	}
+
+	testCases := []struct {
+		name     string
+		x, y     int
+		expected color.Color
+	}{
+		{name: "background", x: 1, y: 1, expected: style.Board},
+		{name: "tile", x: 5 + 8, y: 50 + 8, expected: style.Tile(2).Fill},
+		{name: "large tile", x: 70 + 8, y: 50 + 8, expected: style.Tile(131072).Fill},
+		{name: "blocked cell", x: 5 + 8, y: 115 + 8, expected: style.BlockedCell},
+		{name: "empty cell", x: 70 + 30, y: 115 + 30, expected: style.EmptyCell},
+		{name: "rounded corner", x: 5, y: 50, expected: style.Board},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := color.NRGBAModel.Convert(img.At(tc.x, tc.y)); actual != color.NRGBAModel.Convert(tc.expected) {
+				t.Errorf("expected %v at %d,%d, found %v", tc.expected, tc.x, tc.y, actual)
+			}
+		})
+	}
+
+	// The label is drawn over the middle of the tile
+	label := false
+	for x := 5; x < 65; x++ {
+		if color.NRGBAModel.Convert(img.At(x, 80)) != color.NRGBAModel.Convert(style.Tile(2).Fill) {
+			label = true
+		}
+	}
+	if !label {
+		t.Error("expected the tile value to be drawn")
+	}
+}
+
+func TestSavePNG(t *testing.T) {
+	dir := t.TempDir()
+	style := testStyle()
+	img := RenderBoard(emptyBoard(4), 0, &style, 40)
+
+	path, err := SavePNG(img, dir, time.Date(2026, 10, 19, 13, 4, 5, 6e6, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := filepath.Join(dir, "2048_2026-10-19_13-04-05.006.png"); path != expected {
+		t.Errorf("expected %s, found %s", expected, path)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	decoded, err := png.Decode(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded.Bounds() != img.Bounds() {
+		t.Errorf("expected the saved image to be %v, found %v", img.Bounds(), decoded.Bounds())
+	}
+
+	if !strings.HasSuffix(path, ".png") {
+		t.Errorf("expected a png file, found %s", path)
+	}
+}
+
+func TestBoardGeometryEdge(t *testing.T) {
+	// 4 cells of 48 and 5 gaps of 4 below a header of 36
+	geo := geometryOf(4, 48)
+
+	testCases := []struct {
+		direction string
+		expected  image.Rectangle
+	}{
+		{direction: "up", expected: image.Rect(0, 36, 212, 40)},
+		{direction: "right", expected: image.Rect(208, 36, 212, 248)},
+		{direction: "down", expected: image.Rect(0, 244, 212, 248)},
+		{direction: "left", expected: image.Rect(0, 36, 4, 248)},
+		{direction: "sideways", expected: image.Rectangle{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.direction, func(t *testing.T) {
+			if actual := geo.edge(tc.direction); actual != tc.expected {
+				t.Errorf("expected %v, found %v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRoundedRect(t *testing.T) {
+	rr := roundedRect{r: image.Rect(0, 0, 10, 10), radius: 4}
+
+	testCases := []struct {
+		name     string
+		x, y     int
+		expected bool
+	}{
+		{name: "top left corner", x: 0, y: 0, expected: false},
+		{name: "bottom right corner", x: 9, y: 9, expected: false},
+		{name: "top edge", x: 5, y: 0, expected: true},
+		{name: "left edge", x: 0, y: 5, expected: true},
+		{name: "center", x: 5, y: 5, expected: true},
+		{name: "inside the corner", x: 1, y: 1, expected: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if a := rr.At(tc.x, tc.y).(color.Alpha).A; (a == 0xff) != tc.expected {
+				t.Errorf("expected opaque to be %t, found alpha %d", tc.expected, a)
+			}
+		})
+	}
+}
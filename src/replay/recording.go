@@ -0,0 +1,163 @@
+package replay
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// The last classic game is kept in the config directory so it can be exported later
+var LAST_GAME_FILE = "last_game.json"
+
+// Board value used in files for cells that tiles can not enter
+const BLOCKED_VALUE = -1
+
+// Directions a frame can hold, the names the game gives its moves
+var MOVES = []string{"up", "right", "down", "left"}
+
+// One board of a recording, Move is the direction played on it and empty on the last board
+type Frame struct {
+	Board [][]int
+	Score int
+	Move  string
+}
+
+// Every board of a game in the order they were played and the style to draw them in
+type Recording struct {
+	frames []Frame
+	style  Style
+}
+
+type recordedFrame struct {
+	Board [][]int `json:"board"`
+	Score int     `json:"score"`
+	Move  string  `json:"move,omitempty"`
+}
+
+// On disk representation of a recording, boards use the same values as position files
+type recordingFile struct {
+	Style  *StyleFile      `json:"style"`
+	Frames []recordedFrame `json:"frames"`
+}
+
+func NewRecording(frames []Frame, style Style) Recording {
+	return Recording{frames: frames, style: style}
+}
+
+func ParseRecording(data []byte) (Recording, error) {
+	var f recordingFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return Recording{}, fmt.Errorf("invalid recording: %w", err)
+	}
+
+	if len(f.Frames) == 0 {
+		return Recording{}, errors.New("recording has no frames")
+	}
+
+	if f.Style == nil {
+		return Recording{}, errors.New("recording has no style")
+	}
+
+	style, err := ParseStyle(*f.Style, Style{}, nil)
+	if err != nil {
+		return Recording{}, fmt.Errorf("style: %w", err)
+	}
+
+	r := Recording{frames: make([]Frame, len(f.Frames)), style: style}
+	for i, frame := range f.Frames {
+		if err := CheckBoard(frame.Board); err != nil {
+			return Recording{}, fmt.Errorf("frame %d: %w", i, err)
+		}
+
+		if i < len(f.Frames)-1 && !slices.Contains(MOVES, frame.Move) {
+			return Recording{}, fmt.Errorf("frame %d: invalid move %q", i, frame.Move)
+		}
+
+		r.frames[i] = Frame{Board: frame.Board, Score: frame.Score, Move: frame.Move}
+	}
+
+	return r, nil
+}
+
+func LoadRecording(path string) (Recording, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Recording{}, err
+	}
+
+	r, err := ParseRecording(data)
+	if err != nil {
+		return Recording{}, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return r, nil
+}
+
+// Checks the board is square with at least 2 rows and holds only tile values, 0 and BLOCKED_VALUE
+func CheckBoard(board [][]int) error {
+	size := len(board)
+	if size < 2 {
+		return errors.New("board needs at least 2 rows")
+	}
+
+	for i, row := range board {
+		if len(row) != size {
+			return fmt.Errorf("board is not square, row %d has %d cells", i, len(row))
+		}
+
+		for j, val := range row {
+			if !IsTileValue(val) && val != 0 && val != BLOCKED_VALUE {
+				return fmt.Errorf("invalid tile value %d at row %d col %d", val, i, j)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Tile values are powers of two from 2 up
+func IsTileValue(val int) bool {
+	return val >= 2 && val&(val-1) == 0
+}
+
+// impl Recording
+
+func (r Recording) Len() int {
+	return len(r.frames)
+}
+
+func (r Recording) Frame(i int) Frame {
+	return r.frames[i]
+}
+
+func (r Recording) Style() Style {
+	return r.style
+}
+
+func (r Recording) Marshal() ([]byte, error) {
+	style := r.style.file()
+	f := recordingFile{Style: &style, Frames: make([]recordedFrame, len(r.frames))}
+	for i, frame := range r.frames {
+		f.Frames[i] = recordedFrame{Board: frame.Board, Score: frame.Score, Move: frame.Move}
+	}
+
+	return json.MarshalIndent(f, "", "  ")
+}
+
+func (r Recording) Save(path string) error {
+	data, err := r.Marshal()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o644)
+}
+
+// end
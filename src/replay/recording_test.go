@@ -0,0 +1,114 @@
+package replay
+
+import (
+	"bytes"
+	"image/color"
+	"strings"
+	"testing"
+)
+
+// Style with a distinct colour for every part so tests can tell them apart
+func testStyle() Style {
+	return Style{
+		Board:       color.NRGBA{0xbb, 0xad, 0xa0, 0xff},
+		EmptyCell:   color.NRGBA{0xcd, 0xc1, 0xb4, 0xff},
+		BlockedCell: color.NRGBA{0x3c, 0x3a, 0x32, 0xff},
+		InfoBox:     color.NRGBA{0x8f, 0x7a, 0x66, 0xff},
+		InfoLabel:   color.NRGBA{0xee, 0xe4, 0xda, 0xff},
+		InfoText:    color.NRGBA{0xff, 0xff, 0xff, 0xff},
+		Highlight:   color.NRGBA{0xed, 0xc2, 0x2e, 0xff},
+		Tiles: map[int]TileStyle{
+			2: {Fill: color.NRGBA{0xee, 0xe4, 0xda, 0xff}, Text: color.NRGBA{0x77, 0x6e, 0x65, 0xff}},
+			4: {Fill: color.NRGBA{0xed, 0xe0, 0xc8, 0xff}, Text: color.NRGBA{0x77, 0x6e, 0x65, 0xff}},
+		},
+		Fallback:     TileStyle{Fill: color.NRGBA{0x3c, 0x3a, 0x32, 0xff}, Text: color.NRGBA{0xf9, 0xf6, 0xf2, 0xff}},
+		CornerRadius: 0.05,
+	}
+}
+
+func emptyBoard(size int) [][]int {
+	board := make([][]int, size)
+	for i := range board {
+		board[i] = make([]int, size)
+	}
+
+	return board
+}
+
+// Two 2s in the top row pushed to the right, then merged to the left
+func testRecording() Recording {
+	return NewRecording([]Frame{
+		{Board: [][]int{{2, 2, 0, 0}, {0, 0, 0, 0}, {0, 0, BLOCKED_VALUE, 0}, {0, 0, 0, 0}}, Score: 0, Move: "right"},
+		{Board: [][]int{{0, 0, 0, 4}, {0, 2, 0, 0}, {0, 0, BLOCKED_VALUE, 0}, {0, 0, 0, 0}}, Score: 4},
+	}, testStyle())
+}
+
+func TestRecordingRoundTrip(t *testing.T) {
+	data, err := testRecording().Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := ParseRecording(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	again, err := r.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(data, again) {
+		t.Errorf("expected the recording to survive a round trip\n%s\n%s", data, again)
+	}
+
+	style, expectedStyle := r.Style(), testStyle()
+	testCases := []struct {
+		name     string
+		actual   any
+		expected any
+	}{
+		{name: "frames", actual: r.Len(), expected: 2},
+		{name: "move", actual: r.Frame(0).Move, expected: "right"},
+		{name: "score", actual: r.Frame(1).Score, expected: 4},
+		{name: "blocked cell", actual: r.Frame(1).Board[2][2], expected: BLOCKED_VALUE},
+		{name: "tile style", actual: style.Tile(4), expected: expectedStyle.Tile(4)},
+		{name: "corner radius", actual: style.CornerRadius, expected: 0.05},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.actual != tc.expected {
+				t.Errorf("expected %v, found %v", tc.expected, tc.actual)
+			}
+		})
+	}
+}
+
+func TestParseRecordingErrors(t *testing.T) {
+	style := `"style": {"board": "#bbada0", "empty_cell": "#cdc1b4", "blocked_cell": "#3c3a32", "info_box": "#8f7a66", "info_label": "#eee4da", "info_text": "#ffffff", "highlight": "#edc22e", "fallback": {"fill": "#3c3a32", "text": "#f9f6f2"}}`
+
+	testCases := []struct {
+		name     string
+		data     string
+		expected string
+	}{
+		{name: "not json", data: `frames`, expected: "invalid recording"},
+		{name: "empty", data: `{` + style + `, "frames": []}`, expected: "no frames"},
+		{name: "no style", data: `{"frames": [{"board": [[0, 2], [0, 0]]}]}`, expected: "no style"},
+		{name: "bad colour", data: `{"style": {"board": "red"}, "frames": [{"board": [[0, 2], [0, 0]]}]}`, expected: `style: board: invalid colour "red"`},
+		{name: "bad move", data: `{` + style + `, "frames": [{"board": [[0, 2], [0, 0]], "move": "sideways"}, {"board": [[2, 0], [0, 0]]}]}`, expected: `frame 0: invalid move "sideways"`},
+		{name: "bad board", data: `{` + style + `, "frames": [{"board": [[0, 3], [0, 0]]}]}`, expected: "frame 0: invalid tile value 3"},
+		{name: "not square", data: `{` + style + `, "frames": [{"board": [[0, 2], [0]]}]}`, expected: "frame 0: board is not square"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseRecording([]byte(tc.data))
+			if err == nil || !strings.Contains(err.Error(), tc.expected) {
+				t.Errorf("expected an error containing %q, found %v", tc.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package replay
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// Colours of a single tile value
+type TileStyle struct {
+	Fill color.NRGBA
+	Text color.NRGBA
+}
+
+// How board images look, the game builds one from its theme
+// Values missing from Tiles use Fallback, CornerRadius is a share of the cell size
+type Style struct {
+	Board        color.NRGBA
+	EmptyCell    color.NRGBA
+	BlockedCell  color.NRGBA
+	InfoBox      color.NRGBA
+	InfoLabel    color.NRGBA
+	InfoText     color.NRGBA
+	Highlight    color.NRGBA
+	Tiles        map[int]TileStyle
+	Fallback     TileStyle
+	CornerRadius float64
+}
+
+// Largest corner radius as a share of the cell size, a circle
+const MAX_CORNER_RADIUS = 0.5
+
+type TileStyleEntry struct {
+	Fill string `json:"fill"`
+	Text string `json:"text,omitempty"`
+}
+
+// On disk representation of a style, shared by recordings and the game's themes
+// Every field is optional, missing ones keep the style the file is applied to
+// Colours are written as #rrggbb or #rrggbbaa, corner_radius is a share of the cell size
+type StyleFile struct {
+	Board        string                    `json:"board,omitempty"`
+	EmptyCell    string                    `json:"empty_cell,omitempty"`
+	BlockedCell  string                    `json:"blocked_cell,omitempty"`
+	InfoBox      string                    `json:"info_box,omitempty"`
+	InfoLabel    string                    `json:"info_label,omitempty"`
+	InfoText     string                    `json:"info_text,omitempty"`
+	Highlight    string                    `json:"highlight,omitempty"`
+	Tiles        map[string]TileStyleEntry `json:"tiles,omitempty"`
+	Fallback     *TileStyleEntry           `json:"fallback,omitempty"`
+	CornerRadius *float64                  `json:"corner_radius,omitempty"`
+}
+
+// Parses #rrggbb and #rrggbbaa colours
+func ParseHexColor(s string) (color.NRGBA, error) {
+	hex, ok := strings.CutPrefix(s, "#")
+	if !ok || (len(hex) != 6 && len(hex) != 8) {
+		return color.NRGBA{}, fmt.Errorf("invalid colour %q, expected #rrggbb or #rrggbbaa", s)
+	}
+
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.NRGBA{}, fmt.Errorf("invalid colour %q, expected #rrggbb or #rrggbbaa", s)
+	}
+
+	return color.NRGBA{uint8(v >> 24), uint8(v >> 16), uint8(v >> 8), uint8(v)}, nil
+}
+
+func FormatHexColor(c color.NRGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x%02x", c.R, c.G, c.B, c.A)
+}
+
+// Parses a style file field by field over base
+// Tiles replace the whole table, without a fallback the largest tile keeps its look for every bigger value
+// A tile text colour that is left out or set to "auto" is picked by autoText, it is required when autoText is nil
+func ParseStyle(f StyleFile, base Style, autoText func(fill color.NRGBA) color.NRGBA) (Style, error) {
+	s := base
+	fields := []struct {
+		name  string
+		value string
+		dst   *color.NRGBA
+	}{
+		{"board", f.Board, &s.Board},
+		{"empty_cell", f.EmptyCell, &s.EmptyCell},
+		{"blocked_cell", f.BlockedCell, &s.BlockedCell},
+		{"info_box", f.InfoBox, &s.InfoBox},
+		{"info_label", f.InfoLabel, &s.InfoLabel},
+		{"info_text", f.InfoText, &s.InfoText},
+		{"highlight", f.Highlight, &s.Highlight},
+	}
+
+	for _, field := range fields {
+		if field.value == "" {
+			continue
+		}
+
+		c, err := ParseHexColor(field.value)
+		if err != nil {
+			return Style{}, fmt.Errorf("%s: %w", field.name, err)
+		}
+		*field.dst = c
+	}
+
+	if f.Tiles != nil {
+		s.Tiles = make(map[int]TileStyle, len(f.Tiles))
+		for key, entry := range f.Tiles {
+			val, err := strconv.Atoi(key)
+			if err != nil || !IsTileValue(val) {
+				return Style{}, fmt.Errorf("tiles: invalid tile value %q", key)
+			}
+
+			style, err := parseTileStyle(entry, autoText)
+			if err != nil {
+				return Style{}, fmt.Errorf("tiles %d: %w", val, err)
+			}
+			s.Tiles[val] = style
+		}
+
+		if f.Fallback == nil {
+			largest := 0
+			for val := range s.Tiles {
+				largest = max(largest, val)
+			}
+
+			if largest > 0 {
+				s.Fallback = s.Tiles[largest]
+			}
+		}
+	}
+
+	if f.Fallback != nil {
+		style, err := parseTileStyle(*f.Fallback, autoText)
+		if err != nil {
+			return Style{}, fmt.Errorf("fallback: %w", err)
+		}
+		s.Fallback = style
+	}
+
+	if f.CornerRadius != nil {
+		if *f.CornerRadius < 0 || *f.CornerRadius > MAX_CORNER_RADIUS {
+			return Style{}, fmt.Errorf("corner_radius must be a share of the cell size from 0 to %g, found %g", MAX_CORNER_RADIUS, *f.CornerRadius)
+		}
+		s.CornerRadius = *f.CornerRadius
+	}
+
+	return s, nil
+}
+
+func parseTileStyle(entry TileStyleEntry, autoText func(fill color.NRGBA) color.NRGBA) (TileStyle, error) {
+	fill, err := ParseHexColor(entry.Fill)
+	if err != nil {
+		return TileStyle{}, err
+	}
+
+	if autoText != nil && (entry.Text == "" || entry.Text == "auto") {
+		return TileStyle{Fill: fill, Text: autoText(fill)}, nil
+	}
+
+	txt, err := ParseHexColor(entry.Text)
+	if err != nil {
+		return TileStyle{}, err
+	}
+
+	return TileStyle{Fill: fill, Text: txt}, nil
+}
+
+// impl Style
+
+// Style of a tile value, the fallback for values without one
+func (s *Style) Tile(val int) TileStyle {
+	if style, ok := s.Tiles[val]; ok {
+		return style
+	}
+
+	return s.Fallback
+}
+
+// Corner radius in pixels for a rectangle of the given size
+func (s *Style) Radius(size int) float64 {
+	return s.CornerRadius * float64(size)
+}
+
+func (s *Style) file() StyleFile {
+	radius := s.CornerRadius
+	f := StyleFile{
+		Board:        FormatHexColor(s.Board),
+		EmptyCell:    FormatHexColor(s.EmptyCell),
+		BlockedCell:  FormatHexColor(s.BlockedCell),
+		InfoBox:      FormatHexColor(s.InfoBox),
+		InfoLabel:    FormatHexColor(s.InfoLabel),
+		InfoText:     FormatHexColor(s.InfoText),
+		Highlight:    FormatHexColor(s.Highlight),
+		Tiles:        make(map[string]TileStyleEntry, len(s.Tiles)),
+		Fallback:     &TileStyleEntry{Fill: FormatHexColor(s.Fallback.Fill), Text: FormatHexColor(s.Fallback.Text)},
+		CornerRadius: &radius,
+	}
+
+	for val, style := range s.Tiles {
+		f.Tiles[strconv.Itoa(val)] = TileStyleEntry{Fill: FormatHexColor(style.Fill), Text: FormatHexColor(style.Text)}
+	}
+
+	return f
+}
+
+// end
@@ -0,0 +1,82 @@
+package sound
+
+import (
+	"mkoca/2048/src/sound/synth"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+)
+
+// The effects are synthesized in the synth package, which works without an audio device
+type Effect = synth.Effect
+
+const (
+	SLIDE     = synth.SLIDE
+	MERGE     = synth.MERGE
+	SPAWN     = synth.SPAWN
+	WIN       = synth.WIN
+	GAME_OVER = synth.GAME_OVER
+)
+
+type cacheKey struct {
+	effect   Effect
+	exponent int
+}
+
+var context *audio.Context
+var volume = 1.0
+var muted = false
+
+// Encoded effects, each one is only synthesized once
+var cache = map[cacheKey][]byte{}
+
+// Creates the audio context, nothing plays before Init so tests and headless tools stay silent
+func Init() {
+	if context == nil {
+		context = audio.NewContext(synth.SAMPLE_RATE)
+	}
+}
+
+// Volume of every effect played from now on, between 0 and 1
+func SetVolume(v float64) {
+	volume = min(max(v, 0), 1)
+}
+
+func Volume() float64 {
+	return volume
+}
+
+func SetMuted(m bool) {
+	muted = m
+}
+
+func Muted() bool {
+	return muted
+}
+
+// Mutes or unmutes, returns whether the sound is muted now
+func ToggleMute() bool {
+	muted = !muted
+	return muted
+}
+
+// Plays an effect, exponent is the exponent of the merged tile and only matters for MERGE
+func Play(effect Effect, exponent int) {
+	if context == nil || muted || volume == 0 {
+		return
+	}
+
+	key := cacheKey{effect: effect}
+	if effect == MERGE {
+		key.exponent = exponent
+	}
+
+	data, ok := cache[key]
+	if !ok {
+		data = synth.Encode(synth.Samples(effect, exponent))
+		cache[key] = data
+	}
+
+	player := context.NewPlayerFromBytes(data)
+	player.SetVolume(volume)
+	player.Play()
+}
@@ -0,0 +1,144 @@
+package synth
+
+import (
+	"encoding/binary"
+	"math"
+	"time"
+)
+
+// Samples per second of every effect
+var SAMPLE_RATE = 44100
+
+// Merging into a 2 sounds at the base frequency, every doubling of the tile raises it by MERGE_STEP semitones
+var MERGE_BASE_FREQUENCY = 220.0
+var MERGE_STEP = 2.0
+
+// Notes fade in over this long so they start without a click
+var ATTACK = time.Millisecond * 5
+
+type Effect int32
+
+const (
+	SLIDE Effect = iota
+	MERGE
+	SPAWN
+	WIN
+	GAME_OVER
+)
+
+// Shape of one cycle, phase counts cycles and the result is between -1 and 1
+type Wave func(phase float64) float64
+
+// A note that sweeps from one frequency to another, fading in and out
+type Note struct {
+	wave     Wave
+	from     float64
+	to       float64
+	duration time.Duration
+	gain     float64
+}
+
+func Sine(phase float64) float64 {
+	return math.Sin(2 * math.Pi * phase)
+}
+
+func Square(phase float64) float64 {
+	if _, frac := math.Modf(phase); frac < 0.5 {
+		return 1
+	}
+
+	return -1
+}
+
+func Triangle(phase float64) float64 {
+	_, frac := math.Modf(phase)
+	return 4*math.Abs(frac-0.5) - 1
+}
+
+func Tone(wave Wave, from float64, to float64, duration time.Duration, gain float64) Note {
+	return Note{wave: wave, from: from, to: to, duration: duration, gain: gain}
+}
+
+// Number of samples that last d
+func SampleCount(d time.Duration) int {
+	return int(d.Seconds() * float64(SAMPLE_RATE))
+}
+
+// Loudness of sample i of n, rising over ATTACK and then falling to 0 at the end
+func Envelope(i int, n int) float64 {
+	attack := min(SampleCount(ATTACK), n/10)
+	if i < attack {
+		return float64(i) / float64(attack)
+	}
+
+	return float64(n-i) / float64(n-attack)
+}
+
+// Plays the notes one after another, the samples are between -1 and 1
+func Render(notes ...Note) []float64 {
+	var samples []float64
+
+	for _, note := range notes {
+		n := SampleCount(note.duration)
+		phase := 0.0
+
+		for i := range n {
+			t := float64(i) / float64(n)
+			phase += (note.from + (note.to-note.from)*t) / float64(SAMPLE_RATE)
+			samples = append(samples, note.gain*note.wave(phase)*Envelope(i, n))
+		}
+	}
+
+	return samples
+}
+
+// Pitch of a merge into a tile of 2 to the exponent
+func MergeFrequency(exponent int) float64 {
+	return MERGE_BASE_FREQUENCY * math.Pow(2, float64(max(exponent, 1)-1)*MERGE_STEP/12)
+}
+
+// Samples of an effect, exponent only matters for MERGE
+func Samples(effect Effect, exponent int) []float64 {
+	ms := time.Millisecond
+
+	switch effect {
+	case SLIDE:
+		return Render(Tone(Triangle, 320, 160, 60*ms, 0.3))
+	case MERGE:
+		f := MergeFrequency(exponent)
+		return Render(Tone(Sine, f, f*1.05, 120*ms, 0.6))
+	case SPAWN:
+		return Render(Tone(Sine, 660, 880, 50*ms, 0.25))
+	case WIN:
+		// C major arpeggio
+		return Render(
+			Tone(Triangle, 523.25, 523.25, 120*ms, 0.5),
+			Tone(Triangle, 659.25, 659.25, 120*ms, 0.5),
+			Tone(Triangle, 783.99, 783.99, 120*ms, 0.5),
+			Tone(Triangle, 1046.5, 1046.5, 300*ms, 0.5),
+		)
+	case GAME_OVER:
+		return Render(
+			Tone(Square, 392, 392, 180*ms, 0.15),
+			Tone(Square, 329.63, 329.63, 180*ms, 0.15),
+			Tone(Square, 261.63, 261.63, 180*ms, 0.15),
+			Tone(Square, 196, 185, 400*ms, 0.15),
+		)
+	}
+
+	return nil
+}
+
+// 16 bit little endian stereo PCM, the format ebiten's audio players read
+// Samples outside -1 and 1 are clipped
+func Encode(samples []float64) []byte {
+	out := make([]byte, len(samples)*4)
+
+	for i, s := range samples {
+		v := uint16(int16(math.Round(min(max(s, -1), 1) * math.MaxInt16)))
+		binary.LittleEndian.PutUint16(out[i*4:], v)
+		binary.LittleEndian.PutUint16(out[i*4+2:], v)
+	}
+
+	return out
+}
@@ -0,0 +1,144 @@
+package synth
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestWaves(t *testing.T) {
+	testCases := []struct {
+		name     string
+		wave     Wave
+		phase    float64
+		expected float64
+	}{
+		{name: "sine start", wave: Sine, phase: 0, expected: 0},
+		{name: "sine peak", wave: Sine, phase: 0.25, expected: 1},
+		{name: "sine trough", wave: Sine, phase: 1.75, expected: -1},
+		{name: "square high", wave: Square, phase: 0.25, expected: 1},
+		{name: "square low", wave: Square, phase: 2.75, expected: -1},
+		{name: "triangle start", wave: Triangle, phase: 0, expected: 1},
+		{name: "triangle middle", wave: Triangle, phase: 0.5, expected: -1},
+		{name: "triangle quarter", wave: Triangle, phase: 3.25, expected: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := tc.wave(tc.phase); math.Abs(actual-tc.expected) > 1e-9 {
+				t.Errorf("expected %v, found %v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestEnvelope(t *testing.T) {
+	n := SampleCount(100 * time.Millisecond)
+
+	testCases := []struct {
+		name     string
+		sample   int
+		expected float64
+	}{
+		{name: "silent start", sample: 0, expected: 0},
+		{name: "full after the attack", sample: SampleCount(ATTACK), expected: 1},
+		{name: "silent end", sample: n, expected: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := Envelope(tc.sample, n); actual != tc.expected {
+				t.Errorf("expected %v, found %v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestSamples(t *testing.T) {
+	testCases := []struct {
+		name   string
+		effect Effect
+	}{
+		{name: "slide", effect: SLIDE},
+		{name: "merge", effect: MERGE},
+		{name: "spawn", effect: SPAWN},
+		{name: "win", effect: WIN},
+		{name: "game over", effect: GAME_OVER},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			samples := Samples(tc.effect, 5)
+			if len(samples) == 0 {
+				t.Fatal("expected samples")
+			}
+
+			// Starting and ending silent avoids clicks
+			if samples[0] != 0 || math.Abs(samples[len(samples)-1]) > 0.01 {
+				t.Errorf("expected the effect to start and end silent, found %v and %v", samples[0], samples[len(samples)-1])
+			}
+
+			for i, s := range samples {
+				if s < -1 || s > 1 {
+					t.Fatalf("expected samples between -1 and 1, found %v at %d", s, i)
+				}
+			}
+		})
+	}
+}
+
+// Times the samples change sign, twice per cycle for a clean tone
+func zeroCrossings(samples []float64) int {
+	n := 0
+	for i := 1; i < len(samples); i++ {
+		if (samples[i-1] < 0) != (samples[i] < 0) {
+			n++
+		}
+	}
+
+	return n
+}
+
+func TestMergePitchRises(t *testing.T) {
+	if MergeFrequency(1) != MERGE_BASE_FREQUENCY {
+		t.Errorf("expected a merge into a 2 to sound at the base frequency, found %v", MergeFrequency(1))
+	}
+
+	last := 0
+	for exponent := 1; exponent <= 17; exponent++ {
+		crossings := zeroCrossings(Samples(MERGE, exponent))
+		if crossings <= last {
+			t.Errorf("expected a merge into 2^%d to sound higher than the one below, found %d crossings after %d", exponent, crossings, last)
+		}
+		last = crossings
+	}
+}
+
+func TestEncode(t *testing.T) {
+	testCases := []struct {
+		name     string
+		sample   float64
+		expected int16
+	}{
+		{name: "silence", sample: 0, expected: 0},
+		{name: "half", sample: 0.5, expected: 16384},
+		{name: "clipped high", sample: 2, expected: math.MaxInt16},
+		{name: "clipped low", sample: -2, expected: -math.MaxInt16},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data := Encode([]float64{tc.sample})
+			if len(data) != 4 {
+				t.Fatalf("expected 4 bytes for each sample, found %d", len(data))
+			}
+
+			left := int16(binary.LittleEndian.Uint16(data))
+			right := int16(binary.LittleEndian.Uint16(data[2:]))
+			if left != tc.expected || right != tc.expected {
+				t.Errorf("expected %d on both channels, found %d and %d", tc.expected, left, right)
+			}
+		})
+	}
+}
@@ -2,7 +2,18 @@ window.onload = function(e) {
     const go = new Go();
     WebAssembly.instantiateStreaming(fetch('2048.wasm'), go.importObject)
         .then(result => {
-            console.log(result)
             go.run(result.instance)
         })
-}
\ No newline at end of file
+}
+
+// Some mobile browsers only report the new viewport size a moment after the rotation
+// The game lays itself out again on every resize event
+window.addEventListener("orientationchange", function() {
+    setTimeout(() => window.dispatchEvent(new Event("resize")), 100)
+})
+
+if (window.visualViewport) {
+    window.visualViewport.addEventListener("resize", function() {
+        window.dispatchEvent(new Event("resize"))
+    })
+}